@@ -89,3 +89,13 @@ func SitemapIndex(generator func() *sitemap.Index) http.HandlerFunc {
 		w.Write(xml)
 	}
 }
+
+// Stylesheet returns an HTTP handler that serves the default XSL
+// stylesheet. Route it to the path set in Options.Stylesheet.
+func Stylesheet() http.HandlerFunc {
+	xsl := sitemap.DefaultStylesheet()
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xsl")
+		w.Write(xsl)
+	}
+}
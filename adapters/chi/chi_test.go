@@ -303,3 +303,25 @@ func TestSitemapGenerationError(t *testing.T) {
 		t.Error("Expected successful sitemap generation")
 	}
 }
+
+func TestStylesheet(t *testing.T) {
+	req, err := http.NewRequest("GET", "/sitemap.xsl", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	Stylesheet().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/xsl" {
+		t.Errorf("Expected content type text/xsl, got %s", contentType)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "<xsl:stylesheet") {
+		t.Error("Body should contain the default stylesheet")
+	}
+}
@@ -78,3 +78,12 @@ func SitemapIndex(generator func() *sitemap.Index) echo.HandlerFunc {
 		return c.Blob(http.StatusOK, "application/xml", xml)
 	}
 }
+
+// Stylesheet returns an Echo handler that serves the default XSL
+// stylesheet. Route it to the path set in Options.Stylesheet.
+func Stylesheet() echo.HandlerFunc {
+	xsl := sitemap.DefaultStylesheet()
+	return func(c echo.Context) error {
+		return c.Blob(http.StatusOK, "text/xsl", xsl)
+	}
+}
@@ -261,3 +261,24 @@ func TestSitemapIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestStylesheet(t *testing.T) {
+	e := echo.New()
+	e.GET("/sitemap.xsl", Stylesheet())
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xsl", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != "text/xsl" {
+		t.Errorf("Expected content type text/xsl, got %s", contentType)
+	}
+
+	body := rec.Body.String()
+	if !strings.Contains(body, "<xsl:stylesheet") {
+		t.Error("Body should contain the default stylesheet")
+	}
+}
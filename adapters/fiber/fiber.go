@@ -80,3 +80,13 @@ func SitemapIndex(generator func() *sitemap.Index) fiber.Handler {
 		return c.Send(xml)
 	}
 }
+
+// Stylesheet returns a Fiber handler that serves the default XSL
+// stylesheet. Route it to the path set in Options.Stylesheet.
+func Stylesheet() fiber.Handler {
+	xsl := sitemap.DefaultStylesheet()
+	return func(c *fiber.Ctx) error {
+		c.Set("Content-Type", "text/xsl")
+		return c.Send(xsl)
+	}
+}
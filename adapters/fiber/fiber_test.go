@@ -308,3 +308,35 @@ func TestSitemapIndex(t *testing.T) {
 		})
 	}
 }
+
+func TestStylesheet(t *testing.T) {
+	app := fiber.New()
+	app.Get("/sitemap.xsl", Stylesheet())
+
+	req, err := http.NewRequest("GET", "/sitemap.xsl", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to test request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/xsl" {
+		t.Errorf("Expected content type text/xsl, got %s", contentType)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read body: %v", err)
+	}
+	body := string(data)
+	if !strings.Contains(body, "<xsl:stylesheet") {
+		t.Error("Body should contain the default stylesheet")
+	}
+}
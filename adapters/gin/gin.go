@@ -90,3 +90,12 @@ func SitemapIndex(generator func() *sitemap.Index) gin.HandlerFunc {
 		c.Data(http.StatusOK, "application/xml", xml)
 	}
 }
+
+// Stylesheet returns a Gin handler that serves the default XSL stylesheet.
+// Route it to the path set in Options.Stylesheet.
+func Stylesheet() gin.HandlerFunc {
+	xsl := sitemap.DefaultStylesheet()
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/xsl", xsl)
+	}
+}
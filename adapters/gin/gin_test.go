@@ -316,3 +316,30 @@ func TestSitemapGenerationError(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestStylesheet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.GET("/sitemap.xsl", Stylesheet())
+
+	req, err := http.NewRequest("GET", "/sitemap.xsl", nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/xsl" {
+		t.Errorf("Expected content type text/xsl, got %s", contentType)
+	}
+
+	body := w.Body.String()
+	if !strings.Contains(body, "<xsl:stylesheet") {
+		t.Error("Body should contain the default stylesheet")
+	}
+}
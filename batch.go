@@ -0,0 +1,93 @@
+package sitemap
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// BatchMode selects how AddBatch treats items that fail validation.
+type BatchMode int
+
+const (
+	// BatchBestEffort adds every valid item and reports the rejected ones.
+	BatchBestEffort BatchMode = iota
+	// BatchAllOrNothing adds nothing if any item is rejected.
+	BatchAllOrNothing
+)
+
+// RejectedItem is an item AddBatch did not add, with the reason.
+type RejectedItem struct {
+	Index int // position in the batch
+	Item  Item
+	Err   error
+}
+
+// BatchError reports the items rejected by AddBatch. Its Unwrap method
+// exposes every reason, so errors.Is(err, ErrLimitReached) tells whether
+// the sitemap filled up.
+type BatchError struct {
+	Added    int // items added; always 0 for BatchAllOrNothing
+	Rejected []RejectedItem
+}
+
+func (e *BatchError) Error() string {
+	first := e.Rejected[0]
+	return fmt.Sprintf("%d items rejected, %d added; item %d: %v",
+		len(e.Rejected), e.Added, first.Index, first.Err)
+}
+
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Rejected))
+	for i, r := range e.Rejected {
+		errs[i] = r.Err
+	}
+	return errs
+}
+
+// AddBatch adds multiple items, unlike AddItems continuing past items that
+// fail. It returns nil if every item was added, or a *BatchError listing
+// the rejected items by index and reason.
+//
+// In BatchAllOrNothing mode the sitemap is left unchanged when any item is
+// rejected; every rejected item is still reported.
+func (s *Sitemap) AddBatch(items []Item, mode BatchMode) error {
+	return s.addSeq(slices.Values(items), mode)
+}
+
+// AddSeq adds the items produced by seq like AddBatch, without collecting
+// them into a slice first. Rejected items are reported by their position
+// in the sequence.
+func (s *Sitemap) AddSeq(seq iter.Seq[Item], mode BatchMode) error {
+	return s.addSeq(seq, mode)
+}
+
+func (s *Sitemap) addSeq(seq iter.Seq[Item], mode BatchMode) error {
+	target := s
+	if mode == BatchAllOrNothing {
+		target = s.clone()
+	}
+
+	batchErr := &BatchError{}
+	i := 0
+	for item := range seq {
+		if err := target.add(item); err != nil {
+			batchErr.Rejected = append(batchErr.Rejected, RejectedItem{Index: i, Item: item, Err: err})
+		} else {
+			batchErr.Added++
+		}
+		i++
+	}
+
+	if len(batchErr.Rejected) == 0 {
+		if mode == BatchAllOrNothing {
+			s.replace(target)
+		}
+		return nil
+	}
+
+	if mode == BatchAllOrNothing {
+		batchErr.Added = 0
+	}
+	return batchErr
+}
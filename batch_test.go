@@ -0,0 +1,139 @@
+package sitemap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddBatchBestEffort(t *testing.T) {
+	sm := New()
+	items := []Item{
+		{URL: "https://example.com/1"},
+		{URL: "invalid-url"},
+		{URL: "https://example.com/3"},
+		{URL: "https://example.com/4", Priority: 3},
+		{URL: "https://example.com/5"},
+	}
+
+	err := sm.AddBatch(items, BatchBestEffort)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected *BatchError, got %v", err)
+	}
+
+	if sm.Count() != 3 || batchErr.Added != 3 {
+		t.Errorf("Expected 3 added items, got %d (reported %d)", sm.Count(), batchErr.Added)
+	}
+
+	if len(batchErr.Rejected) != 2 {
+		t.Fatalf("Expected 2 rejected items, got %d", len(batchErr.Rejected))
+	}
+
+	if batchErr.Rejected[0].Index != 1 || !errors.Is(batchErr.Rejected[0].Err, ErrRelativeURL) {
+		t.Errorf("Unexpected first rejection %+v", batchErr.Rejected[0])
+	}
+	if batchErr.Rejected[1].Index != 3 || batchErr.Rejected[1].Item.URL != "https://example.com/4" {
+		t.Errorf("Unexpected second rejection %+v", batchErr.Rejected[1])
+	}
+
+	if !errors.Is(err, ErrPriorityRange) {
+		t.Error("BatchError should unwrap to every reason")
+	}
+
+	if !strings.HasPrefix(err.Error(), "2 items rejected, 3 added; item 1: ") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+}
+
+func TestAddBatchLimit(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2})
+	err := sm.AddBatch([]Item{
+		{URL: "https://example.com/1"},
+		{URL: "https://example.com/2"},
+		{URL: "https://example.com/3"},
+	}, BatchBestEffort)
+
+	if !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Expected ErrLimitReached, got %v", err)
+	}
+	if sm.Count() != 2 {
+		t.Errorf("Expected 2 items, got %d", sm.Count())
+	}
+}
+
+func TestAddBatchAllOrNothing(t *testing.T) {
+	sm := NewWithOptions(&Options{Duplicates: KeepLast})
+	sm.AddItem(Item{URL: "https://example.com/1", Priority: 0.1})
+
+	err := sm.AddBatch([]Item{
+		{URL: "https://example.com/1", Priority: 0.9},
+		{URL: "https://example.com/2"},
+		{URL: "ftp://example.com/3"},
+	}, BatchAllOrNothing)
+
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected *BatchError, got %v", err)
+	}
+	if batchErr.Added != 0 || len(batchErr.Rejected) != 1 || batchErr.Rejected[0].Index != 2 {
+		t.Errorf("Unexpected report %+v", batchErr)
+	}
+
+	if sm.Count() != 1 || sm.Items()[0].Priority != 0.1 {
+		t.Errorf("Sitemap should be unchanged after rollback, got %+v", sm.Items())
+	}
+
+	// The duplicate index must be rolled back too.
+	sm.AddItem(Item{URL: "https://example.com/2"})
+	if sm.Count() != 2 {
+		t.Errorf("Expected 2 items, got %d", sm.Count())
+	}
+
+	err = sm.AddBatch([]Item{
+		{URL: "https://example.com/1", Priority: 0.9},
+		{URL: "https://example.com/3"},
+	}, BatchAllOrNothing)
+	if err != nil {
+		t.Fatalf("AddBatch() failed: %v", err)
+	}
+	if sm.Count() != 3 || sm.Items()[0].Priority != 0.9 {
+		t.Errorf("Valid batch should be committed, got %+v", sm.Items())
+	}
+}
+
+func TestConcurrentAddBatch(t *testing.T) {
+	cs := NewConcurrent(nil)
+	err := cs.AddBatch([]Item{{URL: "https://example.com/"}, {URL: ""}}, BatchBestEffort)
+	if err == nil || cs.Count() != 1 {
+		t.Errorf("Expected one added item and an error, got %d and %v", cs.Count(), err)
+	}
+}
+
+func TestAddSeq(t *testing.T) {
+	source := New()
+	for i := range 3 {
+		source.Add(fmt.Sprintf("https://example.com/%d", i), time.Now(), 0.5, Daily)
+	}
+
+	sm := New()
+	if err := sm.AddSeq(source.All(), BatchBestEffort); err != nil {
+		t.Fatalf("AddSeq() failed: %v", err)
+	}
+	if sm.Count() != 3 {
+		t.Errorf("Expected 3 items, got %d", sm.Count())
+	}
+
+	err := sm.AddSeq(slices.Values([]Item{{URL: "https://example.com/new"}, {URL: "/relative"}}), BatchAllOrNothing)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) || batchErr.Rejected[0].Index != 1 {
+		t.Errorf("Expected item 1 to be rejected, got %v", err)
+	}
+	if sm.Count() != 3 {
+		t.Errorf("AddSeq() should roll back, got %d items", sm.Count())
+	}
+}
@@ -0,0 +1,110 @@
+package sitemap
+
+import "time"
+
+// ItemBuilder builds an Item one field at a time:
+//
+//	item, err := sitemap.NewItem("https://example.com/shoes").
+//		LastMod(updated).
+//		Priority(0.8).
+//		Image(sitemap.Image{URL: "https://example.com/shoes/1.jpg"}).
+//		Image(sitemap.Image{URL: "https://example.com/shoes/2.jpg"}).
+//		Hreflang("de", "https://example.com/de/schuhe").
+//		Build()
+//
+// Nothing is checked until Build is called.
+type ItemBuilder struct {
+	item Item
+}
+
+// NewItem starts building an item for loc.
+func NewItem(loc string) *ItemBuilder {
+	return &ItemBuilder{item: Item{URL: loc}}
+}
+
+// LastMod sets the last modification time.
+func (b *ItemBuilder) LastMod(t time.Time) *ItemBuilder {
+	b.item.LastMod = t
+	return b
+}
+
+// Priority sets the priority, from 0.0 to 1.0. It is written even when it
+// is 0.0; without a call the sitemap leaves priority out.
+func (b *ItemBuilder) Priority(p float64) *ItemBuilder {
+	b.item.Priority = p
+	b.item.PrioritySet = true
+	return b
+}
+
+// ChangeFreq sets how often the page is likely to change.
+func (b *ItemBuilder) ChangeFreq(f ChangeFreq) *ItemBuilder {
+	b.item.ChangeFreq = f
+	return b
+}
+
+// Title sets the title, which is used by the HTML output.
+func (b *ItemBuilder) Title(title string) *ItemBuilder {
+	b.item.Title = title
+	return b
+}
+
+// Image appends an image.
+func (b *ItemBuilder) Image(image Image) *ItemBuilder {
+	b.item.Images = append(b.item.Images, image)
+	return b
+}
+
+// Video appends a video.
+func (b *ItemBuilder) Video(video Video) *ItemBuilder {
+	b.item.Videos = append(b.item.Videos, video)
+	return b
+}
+
+// News sets the Google News metadata.
+func (b *ItemBuilder) News(news GoogleNews) *ItemBuilder {
+	b.item.News = &news
+	return b
+}
+
+// Alternate appends an alternate version of the page for media.
+func (b *ItemBuilder) Alternate(media, url string) *ItemBuilder {
+	b.item.Alternates = append(b.item.Alternates, Alternate{Media: media, URL: url})
+	return b
+}
+
+// Hreflang appends a translation of the page in lang.
+func (b *ItemBuilder) Hreflang(lang, url string) *ItemBuilder {
+	b.item.Langs = append(b.item.Langs, Translation{Language: lang, URL: url})
+	return b
+}
+
+// Mobile marks the item for the mobile sitemaps.
+func (b *ItemBuilder) Mobile(t MobileType) *ItemBuilder {
+	b.item.Mobile = t
+	return b
+}
+
+// With applies options, such as WithGoogleNews, to the item.
+func (b *ItemBuilder) With(opts ...Option) *ItemBuilder {
+	for _, opt := range opts {
+		opt(&b.item)
+	}
+	return b
+}
+
+// Build validates the item like Add does on a sitemap without a BaseURL and
+// returns it, so the location must be absolute; use BuildFor for paths. The
+// builder can be reused; later changes don't affect items already built.
+func (b *ItemBuilder) Build() (Item, error) {
+	return prepareItem(cloneItem(b.item), &Options{})
+}
+
+// BuildFor validates the item like Add does on s and returns it, with
+// relative locations resolved against its BaseURL and locations normalized
+// as it would.
+func (b *ItemBuilder) BuildFor(s *Sitemap) (Item, error) {
+	if s == nil {
+		return b.Build()
+	}
+	return prepareItem(cloneItem(b.item), &s.opts)
+}
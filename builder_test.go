@@ -0,0 +1,114 @@
+package sitemap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestItemBuilder(t *testing.T) {
+	updated := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	item, err := NewItem("https://example.com/shoes").
+		LastMod(updated).
+		Priority(0.8).
+		ChangeFreq(Weekly).
+		Title("Shoes").
+		Image(Image{URL: "https://example.com/shoes/1.jpg"}).
+		Image(Image{URL: "https://example.com/shoes/2.jpg"}).
+		Video(Video{Title: "Trailer", ThumbnailURL: "https://example.com/t.jpg"}).
+		Alternate("print", "https://example.com/shoes/print").
+		Hreflang("de", "https://example.com/de/schuhe").
+		Hreflang(XDefault, "https://example.com/shoes").
+		With(WithGoogleNews(GoogleNews{Title: "New shoes"})).
+		Build()
+	if err != nil {
+		t.Fatalf("Build() failed: %v", err)
+	}
+
+	if item.URL != "https://example.com/shoes" || !item.LastMod.Equal(updated) || item.Priority != 0.8 ||
+		item.ChangeFreq != Weekly || item.Title != "Shoes" {
+		t.Errorf("Unexpected fields %+v", item)
+	}
+	if len(item.Images) != 2 || len(item.Videos) != 1 || len(item.Alternates) != 1 || len(item.Langs) != 2 || item.News == nil {
+		t.Errorf("Unexpected extensions %+v", item)
+	}
+
+	sm := New()
+	if err := sm.AddItem(item); err != nil {
+		t.Errorf("AddItem() failed: %v", err)
+	}
+}
+
+func TestItemBuilderValidation(t *testing.T) {
+	if _, err := NewItem("https://example.com/").Priority(1.5).Build(); !errors.Is(err, ErrPriorityRange) {
+		t.Errorf("Expected ErrPriorityRange, got %v", err)
+	}
+	if _, err := NewItem("/relative").Build(); !errors.Is(err, ErrRelativeURL) {
+		t.Errorf("Expected ErrRelativeURL, got %v", err)
+	}
+	if _, err := NewItem("https://example.com/").Hreflang("german", "https://example.com/de").Build(); !errors.Is(err, ErrLanguage) {
+		t.Errorf("Expected ErrLanguage, got %v", err)
+	}
+}
+
+func TestItemBuilderBuildFor(t *testing.T) {
+	sm := NewWithOptions(&Options{BaseURL: "https://example.com"})
+
+	item, err := NewItem("/products/42").Image(Image{URL: "/img/42.jpg"}).BuildFor(sm)
+	if err != nil {
+		t.Fatalf("BuildFor() failed: %v", err)
+	}
+	if item.URL != "https://example.com/products/42" || item.Images[0].URL != "https://example.com/img/42.jpg" {
+		t.Errorf("Relative locations should be resolved, got %s and %s", item.URL, item.Images[0].URL)
+	}
+	if err := sm.AddItem(item); err != nil {
+		t.Errorf("AddItem() failed: %v", err)
+	}
+
+	if _, err := NewItem("/products/42").BuildFor(New()); !errors.Is(err, ErrRelativeURL) {
+		t.Errorf("Expected ErrRelativeURL without a BaseURL, got %v", err)
+	}
+	if _, err := NewItem("/products/42").BuildFor(nil); !errors.Is(err, ErrRelativeURL) {
+		t.Errorf("Expected ErrRelativeURL for a nil sitemap, got %v", err)
+	}
+}
+
+func TestItemBuilderReuse(t *testing.T) {
+	b := NewItem("https://example.com/").Image(Image{URL: "https://example.com/1.jpg"})
+	first, _ := b.Build()
+	second, _ := b.Image(Image{URL: "https://example.com/2.jpg"}).Build()
+
+	if len(first.Images) != 1 || len(second.Images) != 2 {
+		t.Errorf("Built items should not share images, got %d and %d", len(first.Images), len(second.Images))
+	}
+}
+
+func TestAppendingOptions(t *testing.T) {
+	sm := New()
+	err := sm.Add("https://example.com/", time.Now(), 0.5, Daily,
+		WithImage(Image{URL: "https://example.com/1.jpg"}),
+		WithImage(Image{URL: "https://example.com/2.jpg"}),
+		WithVideo(Video{Title: "Clip", ThumbnailURL: "https://example.com/t.jpg"}),
+		WithAlternate(Alternate{Media: "print", URL: "https://example.com/print"}),
+		WithTranslation(Translation{Language: "fr", URL: "https://example.com/fr"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := sm.Items()[0]
+	if len(item.Images) != 2 || len(item.Videos) != 1 || len(item.Alternates) != 1 || len(item.Langs) != 1 {
+		t.Errorf("Appending options not applied: %+v", item)
+	}
+
+	// Replacing options still replace.
+	sm.Clear()
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily,
+		WithImage(Image{URL: "https://example.com/1.jpg"}),
+		WithImages([]Image{{URL: "https://example.com/2.jpg"}}),
+	)
+	if images := sm.Items()[0].Images; len(images) != 1 || images[0].URL != "https://example.com/2.jpg" {
+		t.Errorf("WithImages() should replace the images, got %+v", images)
+	}
+}
@@ -0,0 +1,124 @@
+package sitemap
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+// ConcurrentSitemap is a sitemap that is safe for use by multiple goroutines.
+// Producers can add items concurrently while others render snapshots.
+type ConcurrentSitemap struct {
+	mu sync.Mutex
+	sm *Sitemap
+}
+
+// NewConcurrent creates a goroutine-safe sitemap. A nil opts uses the defaults.
+func NewConcurrent(opts *Options) *ConcurrentSitemap {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return &ConcurrentSitemap{sm: NewWithOptions(opts)}
+}
+
+// Add adds a URL to the sitemap with the specified parameters.
+func (c *ConcurrentSitemap) Add(loc string, lastMod time.Time, priority float64, changeFreq ChangeFreq, opts ...Option) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Add(loc, lastMod, priority, changeFreq, opts...)
+}
+
+// AddItem adds a pre-configured item to the sitemap.
+func (c *ConcurrentSitemap) AddItem(item Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.AddItem(item)
+}
+
+// AddItems adds multiple items to the sitemap without interleaving them
+// with items added by other goroutines.
+func (c *ConcurrentSitemap) AddItems(items []Item) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.AddItems(items)
+}
+
+// AddBatch adds multiple items like Sitemap.AddBatch, without interleaving
+// them with items added by other goroutines.
+func (c *ConcurrentSitemap) AddBatch(items []Item, mode BatchMode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.AddBatch(items, mode)
+}
+
+// AddSeq adds the items produced by seq like Sitemap.AddSeq. The sitemap
+// stays locked until seq is exhausted, so seq must not call back into it.
+func (c *ConcurrentSitemap) AddSeq(seq iter.Seq[Item], mode BatchMode) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.AddSeq(seq, mode)
+}
+
+// Get returns the item at loc, like Sitemap.Get.
+func (c *ConcurrentSitemap) Get(loc string) (Item, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Get(loc)
+}
+
+// Has reports whether the sitemap holds an item at loc.
+func (c *ConcurrentSitemap) Has(loc string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Has(loc)
+}
+
+// Update changes the item at loc, like Sitemap.Update. fn runs with the
+// sitemap locked, so it must not call back into it.
+func (c *ConcurrentSitemap) Update(loc string, fn func(*Item)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Update(loc, fn)
+}
+
+// Remove removes the item at loc and reports whether there was one.
+func (c *ConcurrentSitemap) Remove(loc string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Remove(loc)
+}
+
+// Count returns the number of URLs in the sitemap.
+func (c *ConcurrentSitemap) Count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Count()
+}
+
+// Clear removes all items from the sitemap.
+func (c *ConcurrentSitemap) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sm.Clear()
+}
+
+// Snapshot returns a copy of the sitemap as it is now. The copy is not
+// affected by later changes and can be rendered with any output method.
+func (c *ConcurrentSitemap) Snapshot() *Sitemap {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.clone()
+}
+
+// Freeze returns a read-only copy of the sitemap as it is now, which can be
+// shared between goroutines.
+func (c *ConcurrentSitemap) Freeze() *Frozen {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sm.Freeze()
+}
+
+// XML generates the XML representation of a snapshot of the sitemap.
+func (c *ConcurrentSitemap) XML() ([]byte, error) {
+	return c.Snapshot().XML()
+}
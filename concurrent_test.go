@@ -0,0 +1,101 @@
+package sitemap
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestConcurrentAdd(t *testing.T) {
+	cs := NewConcurrent(nil)
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				url := fmt.Sprintf("https://example.com/category%d/item%d", g, i)
+				if err := cs.Add(url, now, 0.5, Daily); err != nil {
+					t.Errorf("Add() failed: %v", err)
+				}
+			}
+		}(g)
+	}
+
+	// Render while producers are still adding.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := cs.XML(); err != nil {
+				t.Errorf("XML() failed: %v", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+
+	if cs.Count() != 800 {
+		t.Errorf("Expected 800 items, got %d", cs.Count())
+	}
+}
+
+func TestConcurrentMaxURLs(t *testing.T) {
+	cs := NewConcurrent(&Options{MaxURLs: 50})
+	now := time.Now()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	accepted := 0
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10; i++ {
+				if err := cs.Add(fmt.Sprintf("https://example.com/%d/%d", g, i), now, 0.5, Daily); err == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if accepted != 50 || cs.Count() != 50 {
+		t.Errorf("Expected exactly 50 accepted items, got %d accepted and %d stored", accepted, cs.Count())
+	}
+}
+
+func TestConcurrentSnapshot(t *testing.T) {
+	cs := NewConcurrent(nil)
+	now := time.Now()
+
+	cs.Add("https://example.com/1", now, 0.5, Daily)
+	cs.AddItems([]Item{{URL: "https://example.com/2"}})
+
+	snap := cs.Snapshot()
+
+	cs.Clear()
+	cs.Add("https://example.com/3", now, 0.5, Daily)
+
+	if snap.Count() != 2 {
+		t.Fatalf("Snapshot should keep 2 items, got %d", snap.Count())
+	}
+
+	if snap.Items()[0].URL != "https://example.com/1" {
+		t.Errorf("Snapshot should not be affected by later changes, got %s", snap.Items()[0].URL)
+	}
+
+	xmlData, err := snap.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+	if strings.Contains(string(xmlData), "https://example.com/3") {
+		t.Error("Snapshot XML should not contain items added later")
+	}
+}
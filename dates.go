@@ -0,0 +1,45 @@
+package sitemap
+
+import "time"
+
+// DatePrecision is the W3C Datetime precision used to write dates.
+type DatePrecision int
+
+const (
+	// PrecisionSeconds writes dates like 2024-05-06T07:08:09+02:00.
+	PrecisionSeconds DatePrecision = iota
+	// PrecisionDate writes dates like 2024-05-06.
+	PrecisionDate
+	// PrecisionMinutes writes dates like 2024-05-06T07:08+02:00.
+	PrecisionMinutes
+	// PrecisionFractional writes dates like 2024-05-06T07:08:09.5+02:00,
+	// with as many fractional digits as needed.
+	PrecisionFractional
+)
+
+// DateFormat controls how lastmod and news publication dates are written.
+// The zero value writes seconds in each date's own time zone.
+type DateFormat struct {
+	Precision DatePrecision
+
+	// Location converts every date to a single zone, such as time.UTC or
+	// time.FixedZone("CET", 3600). Nil keeps each date's own zone.
+	Location *time.Location
+}
+
+// format writes t as a W3C Datetime.
+func (f DateFormat) format(t time.Time) string {
+	if f.Location != nil {
+		t = t.In(f.Location)
+	}
+
+	switch f.Precision {
+	case PrecisionDate:
+		return t.Format(time.DateOnly)
+	case PrecisionMinutes:
+		return t.Format("2006-01-02T15:04Z07:00")
+	case PrecisionFractional:
+		return t.Format(time.RFC3339Nano)
+	}
+	return t.Format(time.RFC3339)
+}
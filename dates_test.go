@@ -0,0 +1,78 @@
+package sitemap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateFormat(t *testing.T) {
+	cest := time.FixedZone("CEST", 2*3600)
+	date := time.Date(2024, 5, 6, 7, 8, 9, 500000000, cest)
+
+	tests := []struct {
+		name     string
+		format   DateFormat
+		expected string
+	}{
+		{"default", DateFormat{}, "2024-05-06T07:08:09+02:00"},
+		{"date", DateFormat{Precision: PrecisionDate}, "2024-05-06"},
+		{"minutes", DateFormat{Precision: PrecisionMinutes}, "2024-05-06T07:08+02:00"},
+		{"fractional", DateFormat{Precision: PrecisionFractional}, "2024-05-06T07:08:09.5+02:00"},
+		{"utc", DateFormat{Location: time.UTC}, "2024-05-06T05:08:09Z"},
+		{"date in zone", DateFormat{Precision: PrecisionDate, Location: time.FixedZone("", -8*3600)}, "2024-05-05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.format(date); got != tt.expected {
+				t.Errorf("format() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDateFormatOptions(t *testing.T) {
+	date := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("CEST", 2*3600))
+	dates := DateFormat{Precision: PrecisionMinutes, Location: time.UTC}
+
+	sm := NewWithOptions(&Options{Dates: dates})
+	sm.Add("https://example.com/", date, 0.5, Daily, WithGoogleNews(GoogleNews{
+		SiteName:        "Example",
+		Language:        "en",
+		PublicationDate: date,
+		Title:           "News",
+	}))
+
+	data, _ := sm.XML()
+	for _, expected := range []string{"<lastmod>2024-05-06T05:08Z</lastmod>", "<news:publication_date>2024-05-06T05:08Z</news:publication_date>"} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("XML() should contain %s, got\n%s", expected, data)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Dates: dates})
+	w.Add("https://example.com/", date, 0.5, Daily)
+	w.Close()
+	if !strings.Contains(buf.String(), "<lastmod>2024-05-06T05:08Z</lastmod>") {
+		t.Errorf("Writer should use the date format, got\n%s", buf.String())
+	}
+
+	idx := NewIndexWithOptions(&IndexOptions{Dates: DateFormat{Precision: PrecisionDate}})
+	idx.Add("https://example.com/sitemap.xml", date)
+	data, _ = idx.XML()
+	if !strings.Contains(string(data), "<lastmod>2024-05-06</lastmod>") {
+		t.Errorf("Index XML should use the date format, got\n%s", data)
+	}
+
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{Options: Options{Dates: DateFormat{Precision: PrecisionDate}}, URL: "https://example.com/"})
+	s.Add("https://example.com/", date, 0.5, Daily)
+	s.Close()
+	data, _ = s.Index().XML()
+	if !strings.Contains(string(data), "<lastmod>2024-05-06</lastmod>") {
+		t.Errorf("Splitter index should use the date format, got\n%s", data)
+	}
+}
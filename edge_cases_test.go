@@ -306,14 +306,21 @@ func TestBaseURLWithOptions(t *testing.T) {
 	sm := NewWithOptions(opts)
 	now := time.Now()
 
-	// Test relative URL that should work with base URL
+	// Relative URLs are resolved against the base URL
 	err := sm.Add("/relative/path", now, 1.0, Daily)
+	if err != nil {
+		t.Fatalf("Add() should resolve relative URLs against BaseURL: %v", err)
+	}
+
+	if got := sm.Items()[0].URL; got != "https://example.com/relative/path" {
+		t.Errorf("Expected resolved URL, got %s", got)
+	}
+
+	// The resolved URL is still validated
+	err = sm.Add("ftp://example.com/file", now, 1.0, Daily)
 	if err == nil {
 		t.Error("Add() should still validate URLs even with BaseURL option")
 	}
-
-	// Base URL option doesn't automatically make relative URLs valid
-	// It's more for internal tracking/validation
 }
 
 func TestPreAllocateOption(t *testing.T) {
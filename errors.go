@@ -0,0 +1,81 @@
+package sitemap
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Errors returned by Add, AddItem and Index.Add. Use errors.Is to test for
+// them; the returned errors carry the offending value in their message.
+var (
+	// ErrLimitReached means the sitemap already holds Options.MaxURLs URLs.
+	ErrLimitReached = errors.New("sitemap has reached maximum URL limit")
+
+	// ErrSizeLimitReached means the item would push a Writer past
+	// Options.MaxBytes.
+	ErrSizeLimitReached = errors.New("sitemap has reached maximum size limit")
+
+	// ErrPriorityRange means the priority is outside 0.0 to 1.0.
+	ErrPriorityRange = errors.New("priority must be between 0.0 and 1.0")
+
+	// ErrLanguage means a translation language is not a BCP 47 tag or
+	// x-default.
+	ErrLanguage = errors.New("invalid hreflang")
+
+	// ErrOutranked means the sitemap is full and, under the eviction
+	// policy, every URL in it ranks above the new one, so it was dropped.
+	ErrOutranked = errors.New("full sitemap outranks URL")
+
+	// ErrClosed means a Writer or Splitter was used after Close.
+	ErrClosed = errors.New("sitemap writer is closed")
+
+	// ErrNamespace means a Writer was given an item that needs an
+	// extension namespace Options.Namespaces leaves out. The error is a
+	// *NamespaceError.
+	ErrNamespace = errors.New("extension namespace is not declared")
+
+	// ErrNotFound means Update was given a location the sitemap doesn't
+	// hold.
+	ErrNotFound = errors.New("sitemap has no URL")
+)
+
+// Reasons a URL is rejected, wrapped by URLError.
+var (
+	ErrEmptyURL     = errors.New("URL cannot be empty")
+	ErrMalformedURL = errors.New("invalid URL format")
+	ErrRelativeURL  = errors.New("URL must be absolute")
+	ErrURLScheme    = errors.New("URL scheme must be http or https")
+	ErrURLHost      = errors.New("invalid host")
+	ErrDuplicateURL = errors.New("URL is already in the sitemap")
+)
+
+// URLError reports a rejected URL and the reason, which is one of the
+// ErrEmptyURL, ErrMalformedURL, ErrRelativeURL, ErrURLScheme, ErrURLHost or
+// ErrDuplicateURL errors, possibly wrapping the underlying parse error.
+type URLError struct {
+	URL string
+	Err error
+}
+
+func (e *URLError) Error() string {
+	return "invalid URL " + strconv.Quote(e.URL) + ": " + e.Err.Error()
+}
+
+func (e *URLError) Unwrap() error {
+	return e.Err
+}
+
+// NamespaceError reports an item a Writer rejected because it uses
+// extension namespaces that are not declared on the urlset element.
+type NamespaceError struct {
+	URL     string
+	Missing Namespace
+}
+
+func (e *NamespaceError) Error() string {
+	return ErrNamespace.Error() + " for " + strconv.Quote(e.URL) + ": " + e.Missing.String()
+}
+
+func (e *NamespaceError) Unwrap() error {
+	return ErrNamespace
+}
@@ -0,0 +1,95 @@
+package sitemap
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestURLErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		reason error
+	}{
+		{"empty", "", ErrEmptyURL},
+		{"malformed", "http://[::1:80", ErrMalformedURL},
+		{"relative", "/relative", ErrRelativeURL},
+		{"scheme", "ftp://example.com/", ErrURLScheme},
+		{"host", "https://-é.example/", ErrURLHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sm := New()
+			err := sm.Add(tt.url, time.Now(), 0.5, Daily)
+			if !errors.Is(err, tt.reason) {
+				t.Errorf("Sitemap.Add() error %v should match %v", err, tt.reason)
+			}
+
+			var urlErr *URLError
+			if !errors.As(err, &urlErr) {
+				t.Fatalf("Sitemap.Add() error %v should be a *URLError", err)
+			}
+			if urlErr.URL != tt.url {
+				t.Errorf("URLError.URL = %q, expected %q", urlErr.URL, tt.url)
+			}
+
+			idx := NewIndex()
+			err = idx.Add(tt.url, time.Now())
+			if !errors.Is(err, tt.reason) || !errors.As(err, &urlErr) {
+				t.Errorf("Index.Add() error %v should match %v", err, tt.reason)
+			}
+		})
+	}
+}
+
+func TestLimitErrors(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 1})
+	sm.Add("https://example.com/1", time.Now(), 0.5, Daily)
+
+	err := sm.Add("https://example.com/2", time.Now(), 0.5, Daily)
+	if !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Expected ErrLimitReached, got %v", err)
+	}
+	if err.Error() != "sitemap has reached maximum URL limit of 1" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{MaxBytes: 500})
+	err = w.AddItem(Item{URL: "https://example.com/" + string(bytes.Repeat([]byte("a"), 500))})
+	if !errors.Is(err, ErrSizeLimitReached) {
+		t.Errorf("Expected ErrSizeLimitReached, got %v", err)
+	}
+
+	w.Close()
+	if err := w.AddItem(Item{URL: "https://example.com/"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestPriorityAndLanguageErrors(t *testing.T) {
+	sm := New()
+
+	err := sm.Add("https://example.com/", time.Now(), 1.5, Daily)
+	if !errors.Is(err, ErrPriorityRange) {
+		t.Errorf("Expected ErrPriorityRange, got %v", err)
+	}
+
+	err = sm.AddItem(Item{URL: "https://example.com/", Priority: -0.1})
+	if !errors.Is(err, ErrPriorityRange) {
+		t.Errorf("Expected ErrPriorityRange, got %v", err)
+	}
+
+	err = sm.AddItem(Item{URL: "https://example.com/", Langs: []Translation{{Language: "english", URL: "https://example.com/en"}}})
+	if !errors.Is(err, ErrLanguage) {
+		t.Errorf("Expected ErrLanguage, got %v", err)
+	}
+
+	var urlErr *URLError
+	if errors.As(err, &urlErr) {
+		t.Error("A language error should not be a URLError")
+	}
+}
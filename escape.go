@@ -0,0 +1,122 @@
+package sitemap
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// escapeEntities rewrites the numeric character references encoding/xml
+// uses for quotes into the named entities listed by sitemaps.org. The
+// encoder escapes every literal '&', so these sequences can only be its own
+// escapes.
+func escapeEntities(data []byte) []byte {
+	if !bytes.Contains(data, []byte("&#3")) {
+		return data
+	}
+	data = bytes.ReplaceAll(data, []byte("&#34;"), []byte("&quot;"))
+	return bytes.ReplaceAll(data, []byte("&#39;"), []byte("&apos;"))
+}
+
+// sanitizeText removes characters that are not allowed in XML 1.0, such
+// as control characters and invalid UTF-8, so they don't end up in the
+// output as replacement characters.
+func sanitizeText(s string) string {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !isXMLChar(r, size) {
+			return stripInvalid(s)
+		}
+		i += size
+	}
+	return s
+}
+
+func stripInvalid(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isXMLChar(r, size) {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// isXMLChar reports whether the decoded rune is in the XML 1.0 Char range.
+func isXMLChar(r rune, size int) bool {
+	if r == utf8.RuneError && size == 1 {
+		return false
+	}
+	return r == '\t' || r == '\n' || r == '\r' ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
+
+// encodeURL turns an IRI into an RFC 3986 URI: the host is converted to
+// punycode and non-ASCII characters elsewhere are percent-encoded. ASCII
+// URLs are returned unchanged.
+func encodeURL(rawURL string) (string, error) {
+	if isASCII(rawURL) {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", &URLError{URL: rawURL, Err: fmt.Errorf("%w: %w", ErrMalformedURL, err)}
+	}
+
+	if host := u.Hostname(); !isASCII(host) {
+		ascii, err := idna.Lookup.ToASCII(host)
+		if err != nil {
+			return "", &URLError{URL: rawURL, Err: fmt.Errorf("%w %q: %w", ErrURLHost, host, err)}
+		}
+		if port := u.Port(); port != "" {
+			ascii = net.JoinHostPort(ascii, port)
+		}
+		u.Host = ascii
+	}
+
+	// String percent-encodes the path and fragment but keeps the raw query.
+	u.RawQuery = escapeNonASCII(u.RawQuery)
+
+	return u.String(), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// escapeNonASCII percent-encodes every byte outside the ASCII range.
+func escapeNonASCII(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0xF])
+	}
+	return b.String()
+}
@@ -0,0 +1,185 @@
+package sitemap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXMLEscapesOnce(t *testing.T) {
+	sm := New()
+	err := sm.Add(
+		"https://example.com/search?a=1&b=2",
+		time.Time{},
+		0.5,
+		Daily,
+		WithImages([]Image{{URL: "https://example.com/a.jpg", Title: `Tom & Jerry's "Best" <Moments>`}}),
+	)
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	xmlStr := string(data)
+	if strings.Contains(xmlStr, "&amp;amp;") {
+		t.Error("XML should not double-escape ampersands")
+	}
+
+	if !strings.Contains(xmlStr, "<loc>https://example.com/search?a=1&amp;b=2</loc>") {
+		t.Error("XML should escape the ampersand in loc exactly once")
+	}
+
+	expected := "<image:title>Tom &amp; Jerry&apos;s &quot;Best&quot; &lt;Moments&gt;</image:title>"
+	if !strings.Contains(xmlStr, expected) {
+		t.Errorf("XML should use the sitemaps.org entities, got:\n%s", xmlStr)
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if got := parsed.Items()[0].URL; got != "https://example.com/search?a=1&b=2" {
+		t.Errorf("Round trip should restore the original URL, got %s", got)
+	}
+	if got := parsed.Items()[0].Images[0].Title; got != `Tom & Jerry's "Best" <Moments>` {
+		t.Errorf("Round trip should restore the original title, got %s", got)
+	}
+}
+
+func TestIndexEscapesOnce(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("https://example.com/sitemap.xml?part=1&lang=en", time.Time{})
+
+	data, err := idx.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<loc>https://example.com/sitemap.xml?part=1&amp;lang=en</loc>") {
+		t.Errorf("Index should escape the ampersand exactly once, got:\n%s", data)
+	}
+}
+
+func TestWriterEscapesOnce(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	w.AddItem(Item{
+		URL:    "https://example.com/?a=1&b=2",
+		Videos: []Video{{Title: `"Quoted"`, Description: "It's"}},
+	})
+	w.Close()
+
+	xmlStr := buf.String()
+	if !strings.Contains(xmlStr, "?a=1&amp;b=2") || strings.Contains(xmlStr, "&amp;amp;") {
+		t.Error("Writer should escape ampersands exactly once")
+	}
+	if !strings.Contains(xmlStr, "&quot;Quoted&quot;") || !strings.Contains(xmlStr, "It&apos;s") {
+		t.Error("Writer should use the named quote entities")
+	}
+	if w.Written() != int64(buf.Len()) {
+		t.Errorf("Written() = %d, expected %d", w.Written(), buf.Len())
+	}
+}
+
+func TestSanitizeText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"plain", "plain"},
+		{"tab\tnew\nline\r", "tab\tnew\nline\r"},
+		{"bell\x07 and null\x00", "bell and null"},
+		{"invalid \xff utf-8", "invalid  utf-8"},
+		{"non-character ￾", "non-character "},
+		{"unicode ü 😀", "unicode ü 😀"},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeText(tt.input); got != tt.expected {
+			t.Errorf("sanitizeText(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestXMLDropsInvalidCharacters(t *testing.T) {
+	sm := New()
+	sm.AddItem(Item{
+		URL: "https://example.com/video",
+		Videos: []Video{{
+			ThumbnailURL: "https://example.com/t.jpg",
+			Title:        "Title",
+			Description:  "From the CMS\x0b\x1b with controls",
+			ContentURL:   "https://example.com/v.mp4",
+		}},
+	})
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<video:description>From the CMS with controls</video:description>") {
+		t.Errorf("Control characters should be dropped, got:\n%s", data)
+	}
+
+	if _, err := Parse(bytes.NewReader(data)); err != nil {
+		t.Errorf("Output should be well-formed XML: %v", err)
+	}
+}
+
+func TestEncodeURL(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://example.com/a?b=c", "https://example.com/a?b=c"},
+		{"https://example.com/straße/ü", "https://example.com/stra%C3%9Fe/%C3%BC"},
+		{"https://bücher.example/katalog", "https://xn--bcher-kva.example/katalog"},
+		{"https://bücher.example:8080/", "https://xn--bcher-kva.example:8080/"},
+		{"https://example.com/?q=café", "https://example.com/?q=caf%C3%A9"},
+		{"https://例え.jp/パス", "https://xn--r8jz45g.jp/%E3%83%91%E3%82%B9"},
+	}
+
+	for _, tt := range tests {
+		got, err := encodeURL(tt.input)
+		if err != nil {
+			t.Errorf("encodeURL(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("encodeURL(%q) = %q, expected %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestAddEncodesNonASCII(t *testing.T) {
+	sm := New()
+	err := sm.Add("https://bücher.example/straße", time.Time{}, 0.5, Daily,
+		WithImages([]Image{{URL: "https://example.com/bild-ü.jpg"}}),
+	)
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	item := sm.Items()[0]
+	if item.URL != "https://xn--bcher-kva.example/stra%C3%9Fe" {
+		t.Errorf("Unexpected loc %s", item.URL)
+	}
+	if item.Images[0].URL != "https://example.com/bild-%C3%BC.jpg" {
+		t.Errorf("Unexpected image URL %s", item.Images[0].URL)
+	}
+
+	idx := NewIndex()
+	if err := idx.Add("https://bücher.example/sitemap.xml", time.Time{}); err != nil {
+		t.Fatalf("Index Add() failed: %v", err)
+	}
+	data, _ := idx.XML()
+	if !strings.Contains(string(data), "https://xn--bcher-kva.example/sitemap.xml") {
+		t.Error("Index should convert hosts to punycode")
+	}
+}
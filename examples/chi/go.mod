@@ -9,4 +9,9 @@ require (
 	go.rumenx.com/sitemap v0.0.0-00010101000000-000000000000
 )
 
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+)
+
 replace go.rumenx.com/sitemap => ../..
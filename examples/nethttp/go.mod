@@ -6,4 +6,9 @@ toolchain go1.23.6
 
 require go.rumenx.com/sitemap v0.0.0
 
+require (
+	golang.org/x/net v0.40.0 // indirect
+	golang.org/x/text v0.25.0 // indirect
+)
+
 replace go.rumenx.com/sitemap => ../..
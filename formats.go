@@ -5,6 +5,8 @@
 	"encoding/json"
 	"encoding/xml"
 	"html/template"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -50,7 +52,7 @@ func (s *Sitemap) HTML() ([]byte, error) {
     <div class="url-item">
         <a href="{{.URL}}" class="url" target="_blank">{{.URL}}</a>
         <div class="meta">
-            {{if .Priority}}<strong>Priority:</strong> {{printf "%.1f" .Priority}} | {{end}}
+            {{if or .Priority .PrioritySet}}<strong>Priority:</strong> {{printf "%.1f" .Priority}} | {{end}}
             {{if .ChangeFreq}}<strong>Change Frequency:</strong> {{.ChangeFreq}} | {{end}}
             {{if not .LastMod.IsZero}}<strong>Last Modified:</strong> {{.LastMod.Format "2006-01-02 15:04:05"}}{{end}}
         </div>
@@ -118,49 +120,74 @@ func (s *Sitemap) HTML() ([]byte, error) {
 	return buf.Bytes(), err
 }
 
-// GoogleNews generates a Google News specific sitemap.
+// GoogleNews generates a Google News specific sitemap with the newest
+// 1,000 articles published within Options.NewsWindow. NewsSitemaps returns
+// all of them.
 func (s *Sitemap) GoogleNews() ([]byte, error) {
-	// Filter items that have Google News metadata
-	var newsItems []Item
-	for _, item := range s.items {
-		if item.News != nil {
-			newsItems = append(newsItems, item)
-		}
-	}
+	return firstPart(s, s.NewsSitemaps())
+}
 
-	// Create a temporary sitemap with only news items
-	newsSitemap := &Sitemap{
-		items: newsItems,
-		opts:  s.opts,
-	}
+// Mobile generates a Google mobile sitemap. It lists the mobile alternate
+// of every item, picked by Options.MobileMedia, and every item marked with
+// a MobileType.
+func (s *Sitemap) Mobile() ([]byte, error) {
+	return s.mobile(googleMobileNamespace, false)
+}
 
-	return newsSitemap.XML()
+// BaiduMobile generates a mobile sitemap in the form Baidu reads, where each
+// URL carries its MobileType, for example <mobile:mobile type="pc,mobile"/>.
+// Mobile alternates are listed with type "mobile".
+func (s *Sitemap) BaiduMobile() ([]byte, error) {
+	return s.mobile(baiduMobileNamespace, true)
 }
 
-// Mobile generates a mobile-specific sitemap.
-func (s *Sitemap) Mobile() ([]byte, error) {
+// mobile renders the mobile URLs, with their types if withType is set.
+func (s *Sitemap) mobile(namespace string, withType bool) ([]byte, error) {
+	type mobileTag struct {
+		Type string `xml:"type,attr,omitempty"`
+	}
+	type mobileURL struct {
+		URL    string    `xml:"loc"`
+		Mobile mobileTag `xml:"mobile:mobile"`
+	}
 	urlset := struct {
-		XMLName xml.Name `xml:"urlset"`
-		Xmlns   string   `xml:"xmlns,attr"`
-		Mobile  string   `xml:"xmlns:mobile,attr"`
-		URLs    []struct {
-			URL    string `xml:"loc"`
-			Mobile string `xml:"mobile:mobile,omitempty"`
-		} `xml:"url"`
+		XMLName xml.Name    `xml:"urlset"`
+		Xmlns   string      `xml:"xmlns,attr"`
+		Mobile  string      `xml:"xmlns:mobile,attr"`
+		URLs    []mobileURL `xml:"url"`
 	}{
-		Xmlns:  "http://www.sitemaps.org/schemas/sitemap/0.9",
-		Mobile: "http://www.google.com/schemas/sitemap-mobile/1.0",
+		Xmlns:  sitemapNamespace,
+		Mobile: namespace,
+	}
+
+	isMobile := s.opts.MobileMedia
+	if isMobile == nil {
+		isMobile = IsMobileMedia
+	}
+
+	seen := make(map[string]bool)
+	add := func(loc string, t MobileType) {
+		if seen[loc] {
+			return
+		}
+		seen[loc] = true
+
+		u := mobileURL{URL: sanitizeText(loc)}
+		if withType {
+			u.Mobile.Type = sanitizeText(string(t))
+		}
+		urlset.URLs = append(urlset.URLs, u)
 	}
 
 	for _, item := range s.items {
-		mobileURL := struct {
-			URL    string `xml:"loc"`
-			Mobile string `xml:"mobile:mobile,omitempty"`
-		}{
-			URL:    item.URL,
-			Mobile: "", // This indicates it's a mobile page
+		if item.Mobile != "" {
+			add(item.URL, item.Mobile)
+		}
+		for _, alt := range item.Alternates {
+			if isMobile(alt.Media) {
+				add(alt.URL, MobileOnly)
+			}
 		}
-		urlset.URLs = append(urlset.URLs, mobileURL)
 	}
 
 	var buf bytes.Buffer
@@ -173,7 +200,85 @@ func (s *Sitemap) Mobile() ([]byte, error) {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return escapeEntities(buf.Bytes()), nil
+}
+
+// mobileBreakpoint is the widest viewport, in CSS pixels, that
+// IsMobileMedia treats as a phone.
+const mobileBreakpoint = 640
+
+// IsMobileMedia reports whether a media query targets phones: the handheld
+// media type, or a max-width or max-device-width of at most 640px (40em),
+// as in "only screen and (max-width: 640px)". Queries for other media
+// types, such as print, don't count.
+func IsMobileMedia(media string) bool {
+	for _, query := range strings.Split(strings.ToLower(media), ",") {
+		if isMobileQuery(query) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMobileQuery checks a single query of a media query list.
+func isMobileQuery(query string) bool {
+	// Media features are in parentheses; the words left name the media
+	// type.
+	var features []string
+	for {
+		start := strings.IndexByte(query, '(')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(query[start:], ')')
+		if end < 0 {
+			return false
+		}
+		features = append(features, query[start+1:start+end])
+		query = query[:start] + " " + query[start+end+1:]
+	}
+
+	for _, word := range strings.Fields(query) {
+		switch word {
+		case "handheld":
+			return true
+		case "only", "and", "all", "screen":
+		default:
+			return false
+		}
+	}
+
+	for _, feature := range features {
+		name, value, _ := strings.Cut(feature, ":")
+		switch strings.TrimSpace(name) {
+		case "max-width", "max-device-width":
+			if width, ok := cssPixels(value); ok && width <= mobileBreakpoint {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cssPixels converts a px, em or rem length to CSS pixels, taking an em as
+// the default 16px.
+func cssPixels(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(value, "px"):
+		value = strings.TrimSuffix(value, "px")
+	case strings.HasSuffix(value, "rem"):
+		value, scale = strings.TrimSuffix(value, "rem"), 16
+	case strings.HasSuffix(value, "em"):
+		value, scale = strings.TrimSuffix(value, "em"), 16
+	}
+
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * scale, true
 }
 
 // JSON generates a JSON representation of the sitemap.
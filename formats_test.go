@@ -177,12 +177,12 @@ func TestMobile(t *testing.T) {
 	}
 
 	// Test with items
-	err = sm.Add("https://m.example.com/", now, 1.0, Daily)
+	err = sm.Add("https://m.example.com/", now, 1.0, Daily, WithMobile(MobileOnly))
 	if err != nil {
 		t.Fatalf("Add() failed: %v", err)
 	}
 
-	err = sm.Add("https://m.example.com/products", now, 0.8, Weekly)
+	err = sm.Add("https://m.example.com/products", now, 0.8, Weekly, WithMobile(MobileOnly))
 	if err != nil {
 		t.Fatalf("Add() failed: %v", err)
 	}
@@ -230,6 +230,122 @@ func TestMobile(t *testing.T) {
 	}
 }
 
+func TestMobileAlternates(t *testing.T) {
+	sm := New()
+	now := time.Now()
+
+	sm.Add("https://example.com/page", now, 0.8, Daily,
+		WithAlternate(Alternate{Media: "only screen and (max-width: 640px)", URL: "https://m.example.com/page"}),
+		WithAlternate(Alternate{Media: "print", URL: "https://example.com/page.pdf"}),
+		WithAlternate(Alternate{Media: "(max-width: 1920px)", URL: "https://example.com/page-hd"}),
+		WithAlternate(Alternate{Media: "print and (max-width: 480px)", URL: "https://example.com/page-small.pdf"}))
+	sm.Add("https://example.com/about", now, 0.5, Monthly, WithMobile(MobileResponsive))
+	sm.Add("https://example.com/adapt", now, 0.5, Monthly, WithMobile(MobileAdaptive))
+	sm.Add("https://example.com/desktop", now, 0.5, Monthly)
+
+	type urlset struct {
+		URLs []struct {
+			Loc    string `xml:"loc"`
+			Mobile struct {
+				Type *string `xml:"type,attr"`
+			} `xml:"mobile"`
+		} `xml:"url"`
+	}
+
+	expected := []struct{ loc, typ string }{
+		{"https://m.example.com/page", "mobile"},
+		{"https://example.com/about", "pc,mobile"},
+		{"https://example.com/adapt", "htmladapt"},
+	}
+
+	data, err := sm.Mobile()
+	if err != nil {
+		t.Fatalf("Mobile() failed: %v", err)
+	}
+	var google urlset
+	if err := xml.Unmarshal(data, &google); err != nil {
+		t.Fatalf("Failed to unmarshal mobile XML: %v", err)
+	}
+	if len(google.URLs) != len(expected) {
+		t.Fatalf("Expected %d URLs in mobile sitemap, got %d:\n%s", len(expected), len(google.URLs), data)
+	}
+	for i, u := range google.URLs {
+		if u.Loc != expected[i].loc {
+			t.Errorf("URL %d = %q, expected %q", i, u.Loc, expected[i].loc)
+		}
+		if u.Mobile.Type != nil {
+			t.Errorf("Google mobile sitemap should not write a type, got %q", *u.Mobile.Type)
+		}
+	}
+
+	data, err = sm.BaiduMobile()
+	if err != nil {
+		t.Fatalf("BaiduMobile() failed: %v", err)
+	}
+	if !strings.Contains(string(data), `xmlns:mobile="http://www.baidu.com/schemas/sitemap-mobile/1/"`) {
+		t.Error("Baidu mobile XML should declare the Baidu namespace")
+	}
+	if !strings.Contains(string(data), `<mobile:mobile type="pc,mobile"></mobile:mobile>`) {
+		t.Errorf("Baidu mobile XML should write the type attribute:\n%s", data)
+	}
+	var baidu urlset
+	if err := xml.Unmarshal(data, &baidu); err != nil {
+		t.Fatalf("Failed to unmarshal Baidu mobile XML: %v", err)
+	}
+	if len(baidu.URLs) != len(expected) {
+		t.Fatalf("Expected %d URLs in Baidu mobile sitemap, got %d", len(expected), len(baidu.URLs))
+	}
+	for i, u := range baidu.URLs {
+		if u.Loc != expected[i].loc || u.Mobile.Type == nil || *u.Mobile.Type != expected[i].typ {
+			t.Errorf("URL %d = %q %v, expected %q type %q", i, u.Loc, u.Mobile.Type, expected[i].loc, expected[i].typ)
+		}
+	}
+}
+
+func TestIsMobileMedia(t *testing.T) {
+	tests := []struct {
+		media  string
+		mobile bool
+	}{
+		{"only screen and (max-width: 640px)", true},
+		{"(max-width:480px)", true},
+		{"screen and (max-device-width: 40em)", true},
+		{"handheld", true},
+		{"print, handheld", true},
+		{"(max-width: 1920px)", false},
+		{"screen and (max-width: 1024px)", false},
+		{"print and (max-width: 480px)", false},
+		{"not screen and (max-width: 480px)", false},
+		{"(min-width: 320px)", false},
+		{"(max-width: wide)", false},
+		{"print", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsMobileMedia(tt.media); got != tt.mobile {
+			t.Errorf("IsMobileMedia(%q) = %v, expected %v", tt.media, got, tt.mobile)
+		}
+	}
+}
+
+func TestMobileMediaOption(t *testing.T) {
+	sm := NewWithOptions(&Options{
+		MobileMedia: func(media string) bool { return media == "app" },
+	})
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily,
+		WithAlternate(Alternate{Media: "app", URL: "https://app.example.com/"}),
+		WithAlternate(Alternate{Media: "only screen and (max-width: 640px)", URL: "https://m.example.com/"}))
+
+	data, err := sm.Mobile()
+	if err != nil {
+		t.Fatalf("Mobile() failed: %v", err)
+	}
+	if !strings.Contains(string(data), "https://app.example.com/") || strings.Contains(string(data), "https://m.example.com/") {
+		t.Errorf("Mobile() should list the alternates MobileMedia picks:\n%s", data)
+	}
+}
+
 func TestHTMLWithComplexData(t *testing.T) {
 	sm := New()
 	now := time.Now()
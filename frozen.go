@@ -0,0 +1,118 @@
+package sitemap
+
+import "iter"
+
+// Frozen is a read-only copy of a sitemap. Nothing can change it, so it
+// can be rendered by many goroutines at once, for example by HTTP handlers
+// while a background job builds the next sitemap.
+type Frozen struct {
+	sm *Sitemap
+}
+
+// Freeze returns a read-only copy of the sitemap as it is now. Later
+// changes to the sitemap don't affect it.
+func (s *Sitemap) Freeze() *Frozen {
+	return &Frozen{sm: s.clone()}
+}
+
+// Thaw returns a modifiable copy of the sitemap.
+func (f *Frozen) Thaw() *Sitemap {
+	return f.sm.clone()
+}
+
+// Count returns the number of URLs in the sitemap.
+func (f *Frozen) Count() int {
+	return f.sm.Count()
+}
+
+// Items returns a copy of the items in the sitemap.
+func (f *Frozen) Items() []Item {
+	return f.sm.Items()
+}
+
+// All returns an iterator over copies of the items, in order.
+func (f *Frozen) All() iter.Seq[Item] {
+	return f.sm.All()
+}
+
+// Get returns the item at loc, like Sitemap.Get.
+func (f *Frozen) Get(loc string) (Item, bool) {
+	return f.sm.Get(loc)
+}
+
+// Has reports whether the sitemap holds an item at loc.
+func (f *Frozen) Has(loc string) bool {
+	return f.sm.Has(loc)
+}
+
+// XML generates the XML representation of the sitemap.
+func (f *Frozen) XML() ([]byte, error) {
+	return f.sm.XML()
+}
+
+// XMLGzip generates the gzip-compressed XML representation of the sitemap.
+func (f *Frozen) XMLGzip() ([]byte, error) {
+	return f.sm.XMLGzip()
+}
+
+// TXT generates a plain text representation of the sitemap.
+func (f *Frozen) TXT() ([]byte, error) {
+	return f.sm.TXT()
+}
+
+// HTML generates an HTML representation of the sitemap.
+func (f *Frozen) HTML() ([]byte, error) {
+	return f.sm.HTML()
+}
+
+// BaiduMobile generates a mobile sitemap for Baidu, like
+// Sitemap.BaiduMobile.
+func (f *Frozen) BaiduMobile() ([]byte, error) {
+	return f.sm.BaiduMobile()
+}
+
+// JSON generates a JSON representation of the sitemap.
+func (f *Frozen) JSON() ([]byte, error) {
+	return f.sm.JSON()
+}
+
+// GoogleNews generates a Google News specific sitemap.
+func (f *Frozen) GoogleNews() ([]byte, error) {
+	return f.sm.GoogleNews()
+}
+
+// NewsSitemaps returns the Google News sitemaps, like
+// Sitemap.NewsSitemaps.
+func (f *Frozen) NewsSitemaps() []*Sitemap {
+	return f.sm.NewsSitemaps()
+}
+
+// ImageSitemap generates an image sitemap, like Sitemap.ImageSitemap.
+func (f *Frozen) ImageSitemap() ([]byte, error) {
+	return f.sm.ImageSitemap()
+}
+
+// VideoSitemap generates a video sitemap, like Sitemap.VideoSitemap.
+func (f *Frozen) VideoSitemap() ([]byte, error) {
+	return f.sm.VideoSitemap()
+}
+
+// ImageSitemaps returns the image sitemaps, like Sitemap.ImageSitemaps.
+func (f *Frozen) ImageSitemaps() []*Sitemap {
+	return f.sm.ImageSitemaps()
+}
+
+// VideoSitemaps returns the video sitemaps, like Sitemap.VideoSitemaps.
+func (f *Frozen) VideoSitemaps() []*Sitemap {
+	return f.sm.VideoSitemaps()
+}
+
+// Mobile generates a mobile-specific sitemap.
+func (f *Frozen) Mobile() ([]byte, error) {
+	return f.sm.Mobile()
+}
+
+// Validate checks the sitemap like Sitemap.Validate.
+func (f *Frozen) Validate() []Finding {
+	return f.sm.Validate()
+}
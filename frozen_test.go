@@ -0,0 +1,106 @@
+package sitemap
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestItemsCopy(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily,
+		WithImages([]Image{{URL: "https://example.com/a.jpg"}}),
+		WithGoogleNews(GoogleNews{Title: "News"}),
+	)
+
+	items := sm.Items()
+	items[0].URL = "https://example.com/changed"
+	items[0].Images[0].URL = "https://example.com/changed.jpg"
+	items[0].News.Title = "Changed"
+
+	item := sm.Items()[0]
+	if item.URL != "https://example.com/" || item.Images[0].URL != "https://example.com/a.jpg" || item.News.Title != "News" {
+		t.Errorf("Modifying the result of Items() changed the sitemap: %+v", item)
+	}
+
+	sm.Clear()
+	sm.Add("https://example.com/new", time.Now(), 0.5, Daily)
+	if items[0].URL != "https://example.com/changed" {
+		t.Error("Clear() should not reuse memory held by callers")
+	}
+}
+
+func TestAll(t *testing.T) {
+	sm := New()
+	for i := range 5 {
+		sm.Add(fmt.Sprintf("https://example.com/%d", i), time.Now(), 0.5, Daily)
+	}
+
+	var got []string
+	for item := range sm.All() {
+		got = append(got, item.URL)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if len(got) != 3 || got[2] != "https://example.com/2" {
+		t.Errorf("All() yielded %v", got)
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+
+	frozen := sm.Freeze()
+	before, _ := frozen.XML()
+
+	sm.Add("https://example.com/b", time.Now(), 0.5, Daily)
+	sm.Update("https://example.com/a", func(item *Item) { item.Priority = 1 })
+	sm.Clear()
+
+	after, _ := frozen.XML()
+	if !bytes.Equal(before, after) {
+		t.Error("Changes to the sitemap should not affect a frozen copy")
+	}
+	if frozen.Count() != 1 || !frozen.Has("https://example.com/a") {
+		t.Errorf("Unexpected frozen contents %+v", frozen.Items())
+	}
+
+	thawed := frozen.Thaw()
+	thawed.Add("https://example.com/c", time.Now(), 0.5, Daily)
+	if frozen.Count() != 1 || thawed.Count() != 2 {
+		t.Error("Thaw() should return an independent copy")
+	}
+}
+
+func TestFreezeConcurrentReaders(t *testing.T) {
+	cs := NewConcurrent(&Options{Duplicates: MergeDuplicates})
+	cs.Add("https://example.com/", time.Now(), 0.5, Daily, WithImages([]Image{{URL: "https://example.com/1.jpg"}}))
+
+	var current atomic.Pointer[Frozen]
+	current.Store(cs.Freeze())
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				if _, err := current.Load().XML(); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+
+	for i := range 50 {
+		cs.Add("https://example.com/", time.Now(), 0.5, Daily,
+			WithImages([]Image{{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}}))
+		current.Store(cs.Freeze())
+	}
+	wg.Wait()
+}
@@ -0,0 +1,69 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// XMLGzip generates the gzip-compressed XML representation of the sitemap,
+// suitable for publishing as a .xml.gz file.
+func (s *Sitemap) XMLGzip() ([]byte, error) {
+	data, err := s.XML()
+	if err != nil {
+		return nil, err
+	}
+	return gzipBytes(data)
+}
+
+// XMLGzip generates the gzip-compressed XML representation of the sitemap index.
+func (idx *Index) XMLGzip() ([]byte, error) {
+	data, err := idx.XML()
+	if err != nil {
+		return nil, err
+	}
+	return gzipBytes(data)
+}
+
+// PartName returns the file name of the n-th part of a split sitemap, for
+// example "sitemap-1.xml" or, when compressed, "sitemap-1.xml.gz".
+func PartName(prefix string, n int, compressed bool) string {
+	name := fmt.Sprintf("%s-%d.xml", prefix, n)
+	if compressed {
+		name += ".gz"
+	}
+	return name
+}
+
+// gzipBytes compresses data with the default compression level.
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gzipWriteCloser compresses into an underlying io.WriteCloser and closes
+// both when it is closed.
+type gzipWriteCloser struct {
+	*gzip.Writer
+	dst io.WriteCloser
+}
+
+func newGzipWriteCloser(dst io.WriteCloser) *gzipWriteCloser {
+	return &gzipWriteCloser{Writer: gzip.NewWriter(dst), dst: dst}
+}
+
+func (g *gzipWriteCloser) Close() error {
+	err := g.Writer.Close()
+	if cerr := g.dst.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() failed: %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("Reading gzip data failed: %v", err)
+	}
+	return out
+}
+
+func TestSitemapXMLGzip(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 1.0, Daily)
+
+	expected, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	compressed, err := sm.XMLGzip()
+	if err != nil {
+		t.Fatalf("XMLGzip() failed: %v", err)
+	}
+
+	if !bytes.Equal(gunzip(t, compressed), expected) {
+		t.Error("Decompressed XMLGzip() output should match XML()")
+	}
+}
+
+func TestIndexXMLGzip(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("https://example.com/sitemap-1.xml.gz", time.Now())
+
+	expected, err := idx.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	compressed, err := idx.XMLGzip()
+	if err != nil {
+		t.Fatalf("XMLGzip() failed: %v", err)
+	}
+
+	if !bytes.Equal(gunzip(t, compressed), expected) {
+		t.Error("Decompressed XMLGzip() output should match XML()")
+	}
+}
+
+func TestPartName(t *testing.T) {
+	tests := []struct {
+		prefix     string
+		n          int
+		compressed bool
+		expected   string
+	}{
+		{"sitemap", 1, false, "sitemap-1.xml"},
+		{"sitemap", 2, true, "sitemap-2.xml.gz"},
+		{"products", 10, true, "products-10.xml.gz"},
+	}
+
+	for _, tt := range tests {
+		if got := PartName(tt.prefix, tt.n, tt.compressed); got != tt.expected {
+			t.Errorf("PartName(%q, %d, %v) = %q, expected %q", tt.prefix, tt.n, tt.compressed, got, tt.expected)
+		}
+	}
+}
+
+func TestSplitterCompress(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options:  Options{MaxURLs: 1},
+		URL:      "https://example.com/",
+		Compress: true,
+	})
+
+	s.Add("https://example.com/1", time.Now(), 0.5, Daily)
+	s.Add("https://example.com/2", time.Now(), 0.5, Daily)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if len(files.names) != 2 || files.names[1] != "sitemap-2.xml.gz" {
+		t.Fatalf("Unexpected part names %v", files.names)
+	}
+
+	for _, name := range files.names {
+		f := files.files[name]
+		if !f.closed {
+			t.Errorf("Part %s should be closed", name)
+		}
+
+		data := string(gunzip(t, f.Bytes()))
+		if !strings.HasSuffix(data, "</urlset>") {
+			t.Errorf("Part %s should contain a complete sitemap", name)
+		}
+	}
+
+	xmlData, _ := s.Index().XML()
+	if !strings.Contains(string(xmlData), "<loc>https://example.com/sitemap-1.xml.gz</loc>") {
+		t.Error("Index should reference the compressed parts")
+	}
+}
@@ -0,0 +1,203 @@
+package sitemap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// XDefault is the hreflang value for the page shown to users whose language
+// doesn't match any of the alternates.
+const XDefault = "x-default"
+
+// validateLanguage checks that lang is x-default or a well-formed BCP 47
+// language tag such as "en", "en-GB" or "zh-Hant".
+func validateLanguage(lang string) error {
+	if lang == XDefault {
+		return nil
+	}
+	if lang == "" || strings.Contains(lang, "_") {
+		return fmt.Errorf("%w %q", ErrLanguage, lang)
+	}
+	if _, err := language.Parse(lang); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrLanguage, lang, err)
+	}
+	return nil
+}
+
+// AddHreflangCluster adds one URL for every distinct location in the group,
+// each annotated with the complete set of translations, so the cluster is
+// reciprocal as search engines require. The group may include an XDefault
+// entry. The other parameters apply to every added URL.
+//
+// A member already in the sitemap keeps its item when Options.Duplicates is
+// KeepFirst or MergeDuplicates, and gains the cluster's translations. If it
+// already links a language of the cluster to a different URL, the cluster
+// can't be made reciprocal and an error is returned.
+//
+// Nothing is added if any member is invalid, conflicts with an existing
+// item or doesn't fit.
+func (s *Sitemap) AddHreflangCluster(group []Translation, lastMod time.Time, priority float64, changeFreq ChangeFreq, opts ...Option) error {
+	var items []Item
+	seen := make(map[string]bool)
+	for _, t := range group {
+		item := Item{
+			URL:        t.URL,
+			LastMod:    lastMod,
+			Priority:   priority,
+			ChangeFreq: changeFreq,
+		}
+
+		// Apply options
+		for _, opt := range opts {
+			opt(&item)
+		}
+		item.Langs = group
+
+		prepared, err := prepareItem(item, &s.opts)
+		if err != nil {
+			return fmt.Errorf("hreflang %s: %w", t.Language, err)
+		}
+
+		// Spellings that prepare to the same location are one member.
+		if seen[prepared.URL] {
+			continue
+		}
+		seen[prepared.URL] = true
+		items = append(items, prepared)
+	}
+
+	keep := s.opts.Duplicates == KeepFirst || s.opts.Duplicates == MergeDuplicates
+	if keep {
+		for _, item := range items {
+			if i, ok := s.byLoc[item.URL]; ok {
+				if err := translationConflict(s.items[i], item.Langs); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if s.opts.Overflow == RejectOverflow {
+		// Members already present only take a new slot when duplicates
+		// are allowed.
+		added := len(items)
+		if s.opts.Duplicates != AllowDuplicates {
+			for _, item := range items {
+				if _, ok := s.byLoc[item.URL]; ok {
+					added--
+				}
+			}
+		}
+		if len(s.items)+added > s.opts.MaxURLs {
+			return fmt.Errorf("%w of %d", ErrLimitReached, s.opts.MaxURLs)
+		}
+	}
+
+	// Evictions can drop members part way through, so work on a copy.
+	target := s
+	if s.opts.Overflow != RejectOverflow {
+		target = s.clone()
+	}
+
+	for _, item := range items {
+		if i, ok := target.byLoc[item.URL]; ok && keep {
+			mergeTranslations(&target.items[i], item.Langs)
+			if s.opts.Duplicates == KeepFirst {
+				continue
+			}
+		}
+		if err := target.add(item); err != nil {
+			return err
+		}
+	}
+
+	if target != s {
+		s.replace(target)
+	}
+	return nil
+}
+
+// translationConflict reports an error if existing links a language of
+// langs to a different URL.
+func translationConflict(existing Item, langs []Translation) error {
+	for _, t := range langs {
+		for _, e := range existing.Langs {
+			if strings.EqualFold(e.Language, t.Language) && e.URL != t.URL {
+				return fmt.Errorf("hreflang %s: %s already links it to %s", t.Language, existing.URL, e.URL)
+			}
+		}
+	}
+	return nil
+}
+
+// mergeTranslations appends the translations existing doesn't list yet.
+func mergeTranslations(existing *Item, langs []Translation) {
+	// Clip so appending never writes into a slice another copy still holds.
+	existing.Langs = slices.Clip(existing.Langs)
+	for _, t := range langs {
+		if !slices.ContainsFunc(existing.Langs, func(e Translation) bool {
+			return strings.EqualFold(e.Language, t.Language)
+		}) {
+			existing.Langs = append(existing.Langs, t)
+		}
+	}
+}
+
+// CheckHreflang reports translation links that are not returned. For every
+// item that lists a translation whose URL is also in the sitemap, that URL's
+// item must list the first item in return. Translations pointing outside the
+// sitemap can't be checked and are ignored. It returns nil if every
+// cluster is reciprocal.
+func (s *Sitemap) CheckHreflang() error {
+	var errs []error
+	for _, p := range s.hreflangProblems() {
+		errs = append(errs, errors.New(p.message))
+	}
+	return errors.Join(errs...)
+}
+
+// hreflangProblem is a translation link that is not returned.
+type hreflangProblem struct {
+	index   int // item holding the link
+	lang    int // position of the link in the item's Langs
+	message string
+}
+
+// hreflangProblems finds every translation link that is not returned.
+func (s *Sitemap) hreflangProblems() []hreflangProblem {
+	langs := make(map[string]map[string]bool, len(s.items))
+	for _, item := range s.items {
+		targets := langs[item.URL]
+		if targets == nil {
+			targets = make(map[string]bool, len(item.Langs))
+			langs[item.URL] = targets
+		}
+		for _, t := range item.Langs {
+			targets[t.URL] = true
+		}
+	}
+
+	var problems []hreflangProblem
+	for i, item := range s.items {
+		for j, t := range item.Langs {
+			if t.URL == item.URL {
+				continue
+			}
+			back, ok := langs[t.URL]
+			if ok && !back[item.URL] {
+				problems = append(problems, hreflangProblem{
+					index:   i,
+					lang:    j,
+					message: fmt.Sprintf("%s links to %s (%s) but %s doesn't link back", item.URL, t.URL, t.Language, t.URL),
+				})
+			}
+		}
+	}
+
+	return problems
+}
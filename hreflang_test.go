@@ -0,0 +1,258 @@
+package sitemap
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXMLDeclaresXHTMLNamespace(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 1.0, Daily)
+
+	data, _ := sm.XML()
+	if strings.Contains(string(data), "xmlns:xhtml") {
+		t.Error("XML should not declare the xhtml namespace without links")
+	}
+
+	sm.Add("https://example.com/en", time.Now(), 1.0, Daily,
+		WithTranslations([]Translation{{Language: "de", URL: "https://example.com/de"}}),
+	)
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	xmlStr := string(data)
+	if !strings.Contains(xmlStr, `xmlns:xhtml="http://www.w3.org/1999/xhtml"`) {
+		t.Error("XML should declare the xhtml namespace")
+	}
+	if !strings.Contains(xmlStr, `<xhtml:link rel="alternate" hreflang="de" href="https://example.com/de"></xhtml:link>`) {
+		t.Errorf("XML should contain the hreflang link, got:\n%s", xmlStr)
+	}
+}
+
+func TestValidateLanguage(t *testing.T) {
+	valid := []string{"en", "en-GB", "zh-Hant", "es-419", "de-CH", XDefault}
+	for _, lang := range valid {
+		if err := validateLanguage(lang); err != nil {
+			t.Errorf("validateLanguage(%q) failed: %v", lang, err)
+		}
+	}
+
+	invalid := []string{"", "english", "en_US", "xx", "de-"}
+	for _, lang := range invalid {
+		if err := validateLanguage(lang); err == nil {
+			t.Errorf("validateLanguage(%q) should have failed", lang)
+		}
+	}
+
+	sm := New()
+	err := sm.Add("https://example.com/", time.Now(), 1.0, Daily,
+		WithTranslations([]Translation{{Language: "english", URL: "https://example.com/en"}}),
+	)
+	if err == nil {
+		t.Error("Add() should reject invalid hreflang values")
+	}
+}
+
+func TestAddHreflangCluster(t *testing.T) {
+	sm := New()
+	group := []Translation{
+		{Language: "en", URL: "https://example.com/en/"},
+		{Language: "de", URL: "https://example.com/de/"},
+		{Language: "fr", URL: "https://example.com/fr/"},
+		{Language: XDefault, URL: "https://example.com/en/"},
+	}
+
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.8, Weekly, WithTitle("Home")); err != nil {
+		t.Fatalf("AddHreflangCluster() failed: %v", err)
+	}
+
+	if sm.Count() != 3 {
+		t.Fatalf("Expected one URL per distinct location, got %d", sm.Count())
+	}
+
+	for _, item := range sm.Items() {
+		if len(item.Langs) != len(group) {
+			t.Errorf("%s: expected %d translations, got %d", item.URL, len(group), len(item.Langs))
+		}
+		if item.Title != "Home" {
+			t.Errorf("%s: options should apply to every member", item.URL)
+		}
+	}
+
+	if err := sm.CheckHreflang(); err != nil {
+		t.Errorf("Cluster should be reciprocal: %v", err)
+	}
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+	if strings.Count(string(data), `hreflang="x-default"`) != 3 {
+		t.Error("Every member should carry the x-default link")
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if err := parsed.CheckHreflang(); err != nil {
+		t.Errorf("Parsed cluster should be reciprocal: %v", err)
+	}
+}
+
+func TestAddHreflangClusterAtomic(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2})
+	group := []Translation{
+		{Language: "en", URL: "https://example.com/en"},
+		{Language: "de", URL: "https://example.com/de"},
+		{Language: "fr", URL: "https://example.com/fr"},
+	}
+
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err == nil {
+		t.Error("AddHreflangCluster() should fail when the cluster doesn't fit")
+	}
+	if sm.Count() != 0 {
+		t.Errorf("No members should be added, got %d", sm.Count())
+	}
+
+	group[2].URL = "/relative"
+	sm = New()
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err == nil {
+		t.Error("AddHreflangCluster() should fail for an invalid member")
+	}
+	if sm.Count() != 0 {
+		t.Errorf("No members should be added, got %d", sm.Count())
+	}
+}
+
+func TestAddHreflangClusterExistingMembers(t *testing.T) {
+	group := []Translation{
+		{Language: "en", URL: "https://example.com/en"},
+		{Language: "de", URL: "https://example.com/de"},
+	}
+
+	for _, policy := range []DuplicatePolicy{KeepFirst, KeepLast, MergeDuplicates} {
+		sm := NewWithOptions(&Options{Duplicates: policy})
+		sm.Add("https://example.com/en", time.Now(), 0.5, Daily, WithTitle("English"))
+
+		if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err != nil {
+			t.Fatalf("AddHreflangCluster() failed with policy %d: %v", policy, err)
+		}
+		if sm.Count() != 2 {
+			t.Errorf("Expected 2 URLs with policy %d, got %d", policy, sm.Count())
+		}
+		if err := sm.CheckHreflang(); err != nil {
+			t.Errorf("Cluster should be reciprocal with policy %d: %v", policy, err)
+		}
+
+		item, _ := sm.Get("https://example.com/en")
+		if policy != KeepLast && item.Title != "English" {
+			t.Errorf("The existing item should be kept with policy %d", policy)
+		}
+	}
+
+	// An existing member linking a cluster language elsewhere can't join.
+	sm := NewWithOptions(&Options{Duplicates: KeepFirst})
+	sm.Add("https://example.com/en", time.Now(), 0.5, Daily, WithTranslations([]Translation{
+		{Language: "de", URL: "https://example.com/deutsch"},
+	}))
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err == nil {
+		t.Error("AddHreflangCluster() should fail for a conflicting existing member")
+	}
+	if sm.Count() != 1 || len(sm.Items()[0].Langs) != 1 {
+		t.Errorf("The sitemap should be unchanged, got %v", sm.Items())
+	}
+}
+
+func TestAddHreflangClusterCapacity(t *testing.T) {
+	group := []Translation{
+		{Language: "en", URL: "https://example.com/en"},
+		{Language: "de", URL: "https://example.com/de"},
+	}
+
+	for _, policy := range []DuplicatePolicy{KeepFirst, KeepLast, MergeDuplicates} {
+		sm := NewWithOptions(&Options{MaxURLs: 2, Duplicates: policy})
+		sm.Add("https://example.com/en", time.Now(), 0.5, Daily)
+
+		if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err != nil {
+			t.Errorf("The cluster should fit with policy %d: %v", policy, err)
+		}
+		if sm.Count() != 2 {
+			t.Errorf("Expected 2 URLs with policy %d, got %d", policy, sm.Count())
+		}
+	}
+
+	sm := NewWithOptions(&Options{MaxURLs: 2})
+	sm.Add("https://example.com/en", time.Now(), 0.5, Daily)
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Expected ErrLimitReached when duplicates take a slot, got %v", err)
+	}
+}
+
+func TestAddHreflangClusterSpellings(t *testing.T) {
+	sm := NewWithOptions(&Options{
+		BaseURL:   "https://example.com",
+		Normalize: &Normalization{Lowercase: true},
+	})
+	group := []Translation{
+		{Language: "en", URL: "https://EXAMPLE.com/en"},
+		{Language: XDefault, URL: "/en"},
+		{Language: "de", URL: "https://example.com/de"},
+	}
+
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.5, Daily); err != nil {
+		t.Fatalf("AddHreflangCluster() failed: %v", err)
+	}
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/de", "https://example.com/en"}) {
+		t.Errorf("Spellings of one location should be one member, got %v", got)
+	}
+}
+
+func TestAddHreflangClusterEviction(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority})
+	sm.Add("https://example.com/a", time.Now(), 0.9, Daily)
+	sm.Add("https://example.com/b", time.Now(), 0.9, Daily)
+
+	group := []Translation{
+		{Language: "en", URL: "https://example.com/en"},
+		{Language: "de", URL: "https://example.com/de"},
+	}
+	if err := sm.AddHreflangCluster(group, time.Now(), 0.1, Daily); !errors.Is(err, ErrOutranked) {
+		t.Errorf("Expected ErrOutranked, got %v", err)
+	}
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/a", "https://example.com/b"}) {
+		t.Errorf("No members should be added, got %v", got)
+	}
+}
+
+func TestCheckHreflang(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/en", time.Now(), 0.5, Daily, WithTranslations([]Translation{
+		{Language: "en", URL: "https://example.com/en"},
+		{Language: "de", URL: "https://example.com/de"},
+		{Language: "ja", URL: "https://other.example/ja"},
+	}))
+	sm.Add("https://example.com/de", time.Now(), 0.5, Daily, WithTranslations([]Translation{
+		{Language: "de", URL: "https://example.com/de"},
+	}))
+
+	err := sm.CheckHreflang()
+	if err == nil {
+		t.Fatal("CheckHreflang() should report the missing return link")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "https://example.com/de doesn't link back") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if strings.Contains(msg, "other.example") {
+		t.Error("Links outside the sitemap should not be reported")
+	}
+}
@@ -3,13 +3,23 @@
 import (
 	"bytes"
 	"encoding/xml"
-	"html"
 	"time"
 )
 
 // Index represents a sitemap index that references multiple sitemaps.
 type Index struct {
 	sitemaps []IndexItem
+	opts     IndexOptions
+}
+
+// IndexOptions contains configuration options for the sitemap index.
+type IndexOptions struct {
+	// Dates controls how lastmod dates are written.
+	Dates DateFormat
+
+	// Stylesheet is the URL of an XSL stylesheet referenced from the XML
+	// output, for example "/sitemap.xsl". Empty references none.
+	Stylesheet string
 }
 
 // IndexItem represents a single sitemap reference in the index.
@@ -38,8 +48,21 @@ func NewIndex() *Index {
 	}
 }
 
+// NewIndexWithOptions creates a new sitemap index with custom options.
+func NewIndexWithOptions(opts *IndexOptions) *Index {
+	return &Index{
+		sitemaps: make([]IndexItem, 0),
+		opts:     *opts,
+	}
+}
+
 // Add adds a sitemap URL to the index.
 func (idx *Index) Add(url string, lastMod time.Time) error {
+	url, err := encodeURL(url)
+	if err != nil {
+		return err
+	}
+
 	if err := validateURL(url); err != nil {
 		return err
 	}
@@ -66,17 +89,16 @@ func (idx *Index) XML() ([]byte, error) {
 
 	for i, sitemap := range idx.sitemaps {
 		urlset.Sitemaps[i] = IndexXMLItem{
-			URL: html.EscapeString(sitemap.URL),
+			URL: sanitizeText(sitemap.URL),
 		}
 
 		if !sitemap.LastMod.IsZero() {
-			urlset.Sitemaps[i].LastMod = sitemap.LastMod.Format(time.RFC3339)
+			urlset.Sitemaps[i].LastMod = idx.opts.Dates.format(sitemap.LastMod)
 		}
 	}
 
 	var buf bytes.Buffer
-	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	buf.WriteByte('\n')
+	writeHeader(&buf, idx.opts.Stylesheet)
 
 	encoder := xml.NewEncoder(&buf)
 	encoder.Indent("", "  ")
@@ -84,5 +106,5 @@ func (idx *Index) XML() ([]byte, error) {
 		return nil, err
 	}
 
-	return buf.Bytes(), nil
+	return escapeEntities(buf.Bytes()), nil
 }
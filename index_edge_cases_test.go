@@ -132,12 +132,16 @@ func TestSitemapItemsMethod(t *testing.T) {
 		t.Errorf("Expected 2 items, got %d", len(items))
 	}
 
-	// Note: Items() returns the actual slice (not a copy), so modifying it
-	// would affect the original sitemap. This is current behavior.
 	originalURL := items[0].URL
 	if originalURL != "https://example.com/1" {
 		t.Errorf("Expected first URL to be https://example.com/1, got %s", originalURL)
 	}
+
+	// Items() returns a copy, so modifying it doesn't affect the sitemap.
+	items[0].URL = "https://example.com/changed"
+	if sm.Items()[0].URL != originalURL {
+		t.Error("Modifying the result of Items() changed the sitemap")
+	}
 }
 
 func TestComplexImageAndVideoData(t *testing.T) {
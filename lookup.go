@@ -0,0 +1,147 @@
+package sitemap
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Get returns the item at loc. The location is resolved, encoded and
+// normalized the same way as in Add, so it matches however the item was
+// added. The returned item is a copy.
+//
+// When Options.Duplicates is AllowDuplicates and the location was added
+// more than once, Get and Update see the first item and Remove removes all
+// of them.
+func (s *Sitemap) Get(loc string) (Item, bool) {
+	i, ok := s.find(loc)
+	if !ok {
+		return Item{}, false
+	}
+	return cloneItem(s.items[i]), true
+}
+
+// Has reports whether the sitemap holds an item at loc.
+func (s *Sitemap) Has(loc string) bool {
+	_, ok := s.find(loc)
+	return ok
+}
+
+// Update calls fn with a copy of the item at loc and stores the result,
+// which is validated like a new item. If fn changes the location, the new
+// one must not already be in the sitemap unless duplicates are allowed. On
+// error the item is left unchanged.
+func (s *Sitemap) Update(loc string, fn func(*Item)) error {
+	i, ok := s.find(loc)
+	if !ok {
+		return fmt.Errorf("%w %q", ErrNotFound, loc)
+	}
+
+	item := cloneItem(s.items[i])
+	fn(&item)
+
+	item, err := prepareItem(item, &s.opts)
+	if err != nil {
+		return err
+	}
+
+	old := s.items[i].URL
+	if item.URL != old && s.opts.Duplicates != AllowDuplicates {
+		if _, exists := s.byLoc[item.URL]; exists {
+			return &URLError{URL: item.URL, Err: ErrDuplicateURL}
+		}
+	}
+
+	s.items[i] = item
+	s.changed(i)
+	s.rekey(i, old)
+	return nil
+}
+
+// Remove removes the item at loc, keeping the order of the others. It
+// reports whether there was one.
+//
+// Finding the item is O(1), but removing it is O(n): the later items move
+// down one place and their index entries are updated. The order is kept
+// because every output lists the items in the order they were added, and
+// swapping the last item into the gap would reorder published sitemaps on
+// each removal. To remove many items at once, Filter rebuilds the sitemap
+// in a single pass.
+func (s *Sitemap) Remove(loc string) bool {
+	i, ok := s.find(loc)
+	if !ok {
+		return false
+	}
+
+	url := s.items[i].URL
+	s.ranks = nil
+	if s.dups > 0 {
+		s.items = slices.DeleteFunc(s.items, func(item Item) bool {
+			return item.URL == url
+		})
+		s.reindex()
+		return true
+	}
+
+	s.items = slices.Delete(s.items, i, i+1)
+	delete(s.byLoc, url)
+	for j := i; j < len(s.items); j++ {
+		s.byLoc[s.items[j].URL] = j
+	}
+	return true
+}
+
+// find returns the index of the first item at loc.
+func (s *Sitemap) find(loc string) (int, bool) {
+	key, err := prepareItem(Item{URL: loc}, &s.opts)
+	if err != nil {
+		return 0, false
+	}
+	i, ok := s.byLoc[key.URL]
+	return i, ok
+}
+
+// rekey updates the index after item i moved from location old.
+func (s *Sitemap) rekey(i int, old string) {
+	loc := s.items[i].URL
+	if loc == old {
+		return
+	}
+	if _, exists := s.byLoc[loc]; exists || s.dups > 0 {
+		s.reindex()
+		return
+	}
+	delete(s.byLoc, old)
+	s.byLoc[loc] = i
+}
+
+// reindex rebuilds the location index from the items.
+func (s *Sitemap) reindex() {
+	if s.byLoc == nil {
+		s.byLoc = make(map[string]int, len(s.items))
+	}
+	clear(s.byLoc)
+	s.dups = 0
+	for i, item := range s.items {
+		if _, exists := s.byLoc[item.URL]; exists {
+			s.dups++
+			continue
+		}
+		s.byLoc[item.URL] = i
+	}
+}
+
+// cloneItem returns a copy of the item that shares no slices with it.
+func cloneItem(item Item) Item {
+	item.Images = slices.Clone(item.Images)
+	item.Videos = slices.Clone(item.Videos)
+	for i := range item.Videos {
+		item.Videos[i] = cloneVideo(item.Videos[i])
+	}
+	item.Alternates = slices.Clone(item.Alternates)
+	item.Langs = slices.Clone(item.Langs)
+	if item.News != nil {
+		news := *item.News
+		item.News = &news
+	}
+	return item
+}
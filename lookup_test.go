@@ -0,0 +1,175 @@
+package sitemap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetHas(t *testing.T) {
+	sm := NewWithOptions(&Options{
+		BaseURL:   "https://example.com",
+		Normalize: &Normalization{Lowercase: true},
+	})
+	sm.Add("/about", time.Now(), 0.8, Monthly, WithImages([]Image{{URL: "/a.jpg"}}))
+
+	for _, loc := range []string{"/about", "https://example.com/about", "HTTPS://EXAMPLE.COM/about"} {
+		if !sm.Has(loc) {
+			t.Errorf("Has(%q) = false, expected true", loc)
+		}
+	}
+	if sm.Has("/contact") || sm.Has("") {
+		t.Error("Has() should be false for missing locations")
+	}
+
+	item, ok := sm.Get("/about")
+	if !ok || item.Priority != 0.8 {
+		t.Fatalf("Get() = %+v, %v", item, ok)
+	}
+
+	// The returned item is a copy.
+	item.Images[0].URL = "https://example.com/changed.jpg"
+	if sm.Items()[0].Images[0].URL != "https://example.com/a.jpg" {
+		t.Error("Modifying the result of Get() changed the sitemap")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/b", time.Now(), 0.5, Daily)
+
+	err := sm.Update("https://example.com/a", func(item *Item) {
+		item.Priority = 0.9
+		item.Title = "A"
+	})
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if item, _ := sm.Get("https://example.com/a"); item.Priority != 0.9 || item.Title != "A" {
+		t.Errorf("Update() not applied: %+v", item)
+	}
+
+	err = sm.Update("https://example.com/a", func(item *Item) { item.Priority = 2 })
+	if !errors.Is(err, ErrPriorityRange) {
+		t.Errorf("Expected ErrPriorityRange, got %v", err)
+	}
+	if item, _ := sm.Get("https://example.com/a"); item.Priority != 0.9 {
+		t.Error("A failed Update() should leave the item unchanged")
+	}
+
+	err = sm.Update("https://example.com/missing", func(*Item) {})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateLocation(t *testing.T) {
+	sm := NewWithOptions(&Options{Duplicates: KeepFirst})
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/b", time.Now(), 0.5, Daily)
+
+	err := sm.Update("https://example.com/a", func(item *Item) { item.URL = "https://example.com/b" })
+	if !errors.Is(err, ErrDuplicateURL) {
+		t.Errorf("Expected ErrDuplicateURL, got %v", err)
+	}
+
+	err = sm.Update("https://example.com/a", func(item *Item) { item.URL = "https://example.com/c" })
+	if err != nil {
+		t.Fatalf("Update() failed: %v", err)
+	}
+	if sm.Has("https://example.com/a") || !sm.Has("https://example.com/c") {
+		t.Error("Update() should move the item to its new location")
+	}
+	if sm.Items()[0].URL != "https://example.com/c" {
+		t.Error("Update() should keep the item in place")
+	}
+
+	// The old location is free again.
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+	if sm.Count() != 3 {
+		t.Errorf("Expected 3 items, got %d", sm.Count())
+	}
+}
+
+func TestRemove(t *testing.T) {
+	sm := New()
+	for i := range 5 {
+		sm.Add(fmt.Sprintf("https://example.com/%d", i), time.Now(), 0.5, Daily)
+	}
+
+	if !sm.Remove("https://example.com/1") {
+		t.Fatal("Remove() = false, expected true")
+	}
+	if sm.Remove("https://example.com/1") {
+		t.Error("Removing twice should report false")
+	}
+
+	var got []string
+	for _, item := range sm.Items() {
+		got = append(got, item.URL)
+	}
+	expected := []string{"https://example.com/0", "https://example.com/2", "https://example.com/3", "https://example.com/4"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("Remove() should keep the order, got %v", got)
+	}
+
+	// Items after the removed one are still found.
+	if err := sm.Update("https://example.com/4", func(item *Item) { item.Title = "Four" }); err != nil {
+		t.Fatal(err)
+	}
+	if sm.Items()[3].Title != "Four" {
+		t.Error("Update() after Remove() changed the wrong item")
+	}
+}
+
+func TestRemoveDuplicates(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.1, Daily)
+	sm.Add("https://example.com/b", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/a", time.Now(), 0.2, Daily)
+
+	if item, _ := sm.Get("https://example.com/a"); item.Priority != 0.1 {
+		t.Errorf("Get() should return the first duplicate, got %+v", item)
+	}
+
+	sm.Remove("https://example.com/a")
+	if sm.Count() != 1 || sm.Has("https://example.com/a") {
+		t.Errorf("Remove() should remove every duplicate, got %+v", sm.Items())
+	}
+	if !sm.Has("https://example.com/b") {
+		t.Error("Remove() lost the other item")
+	}
+}
+
+func TestParseLookup(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+	data, _ := sm.XML()
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Has("https://example.com/a") {
+		t.Error("Parsed sitemaps should support lookups")
+	}
+}
+
+func TestConcurrentLookup(t *testing.T) {
+	cs := NewConcurrent(nil)
+	cs.Add("https://example.com/", time.Now(), 0.5, Daily)
+
+	if err := cs.Update("https://example.com/", func(item *Item) { item.Priority = 1 }); err != nil {
+		t.Fatal(err)
+	}
+	if item, ok := cs.Get("https://example.com/"); !ok || item.Priority != 1 {
+		t.Errorf("Get() = %+v, %v", item, ok)
+	}
+	if !cs.Remove("https://example.com/") || cs.Has("https://example.com/") {
+		t.Error("Remove() failed")
+	}
+}
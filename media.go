@@ -0,0 +1,131 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"slices"
+)
+
+// headerAllowance is the room left in each split sitemap for the XML
+// declaration and the urlset element.
+const headerAllowance = 1024
+
+// ImageSitemap generates an image sitemap holding only the URLs that have
+// images, and only their images. ImageSitemaps returns every part when the
+// URLs don't fit in one file.
+func (s *Sitemap) ImageSitemap() ([]byte, error) {
+	return firstPart(s, s.ImageSitemaps())
+}
+
+// ImageSitemaps returns image sitemaps for the URLs that have images.
+// Videos, news and links are left out, so only the image namespace is
+// declared. A URL with more than the 1,000 images allowed per URL is
+// repeated in later parts with the rest of its images. A new part starts
+// when Options.MaxURLs, 50,000 at most, or Options.MaxBytes would be
+// exceeded. It returns nil if no URL has images.
+func (s *Sitemap) ImageSitemaps() []*Sitemap {
+	return s.splitMedia(func(item Item) []Item {
+		var entries []Item
+		for images := range slices.Chunk(item.Images, maxImagesPerURL) {
+			entry := mediaEntry(item)
+			entry.Images = images
+			entries = append(entries, entry)
+		}
+		return entries
+	})
+}
+
+// VideoSitemap generates a video sitemap holding only the URLs that have
+// videos, and only their videos. VideoSitemaps returns every part when the
+// URLs don't fit in one file.
+func (s *Sitemap) VideoSitemap() ([]byte, error) {
+	return firstPart(s, s.VideoSitemaps())
+}
+
+// VideoSitemaps returns video sitemaps for the URLs that have videos.
+// Images, news and links are left out, so only the video namespace is
+// declared. A new part starts when Options.MaxURLs, 50,000 at most, or
+// Options.MaxBytes would be exceeded. It returns nil if no URL has videos.
+func (s *Sitemap) VideoSitemaps() []*Sitemap {
+	return s.splitMedia(func(item Item) []Item {
+		if len(item.Videos) == 0 {
+			return nil
+		}
+		entry := mediaEntry(item)
+		entry.Videos = item.Videos
+		return []Item{entry}
+	})
+}
+
+// mediaEntry copies the fields of the item that every sitemap can hold.
+func mediaEntry(item Item) Item {
+	return Item{
+		URL:         item.URL,
+		LastMod:     item.LastMod,
+		ChangeFreq:  item.ChangeFreq,
+		Priority:    item.Priority,
+		PrioritySet: item.PrioritySet,
+	}
+}
+
+// splitMedia expands every item into the entries returned by expand and
+// packs them into sitemaps within the URL and size limits. The entries of
+// one item go into different parts, so no part repeats a location.
+func (s *Sitemap) splitMedia(expand func(Item) []Item) []*Sitemap {
+	maxBytes := s.opts.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+	maxBytes -= headerAllowance
+	maxURLs := min(s.opts.MaxURLs, defaultMaxURLs)
+
+	var parts [][]Item
+	var sizes []int64
+	for _, item := range s.items {
+		start := 0
+		for _, entry := range expand(item) {
+			size := encodedSize(entry, s.opts.Dates)
+			p := start
+			for p < len(parts) && (len(parts[p]) >= maxURLs || sizes[p]+size > maxBytes) {
+				p++
+			}
+			if p == len(parts) {
+				parts = append(parts, nil)
+				sizes = append(sizes, 0)
+			}
+			parts[p] = append(parts[p], entry)
+			sizes[p] += size
+			start = p + 1
+		}
+	}
+
+	sitemaps := make([]*Sitemap, len(parts))
+	for i, items := range parts {
+		sitemaps[i] = s.derive(items)
+	}
+	if len(sitemaps) == 0 {
+		return nil
+	}
+	return sitemaps
+}
+
+// encodedSize returns the number of bytes the item takes up in XML output.
+func encodedSize(item Item, dates DateFormat) int64 {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("  ", "  ")
+	if err := enc.EncodeElement(toXMLItem(item, dates), xml.StartElement{Name: xml.Name{Local: "url"}}); err != nil {
+		return 0
+	}
+	enc.Flush()
+	return int64(len(escapeEntities(buf.Bytes())) + 1)
+}
+
+// firstPart renders the first of the parts, or an empty sitemap if there
+// are none.
+func firstPart(s *Sitemap, parts []*Sitemap) ([]byte, error) {
+	if len(parts) == 0 {
+		return s.derive(nil).XML()
+	}
+	return parts[0].XML()
+}
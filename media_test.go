@@ -0,0 +1,135 @@
+package sitemap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mediaSitemap() *Sitemap {
+	sm := New()
+	sm.Add("https://example.com/plain", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/gallery", time.Now(), 0.8, Weekly,
+		WithImage(Image{URL: "https://example.com/1.jpg"}),
+		WithGoogleNews(GoogleNews{SiteName: "Example", Language: "en", PublicationDate: time.Now(), Title: "News"}),
+		WithTranslation(Translation{Language: "de", URL: "https://example.com/de/gallery"}),
+	)
+	sm.Add("https://example.com/watch", time.Now(), 0.8, Weekly,
+		WithImage(Image{URL: "https://example.com/2.jpg"}),
+		WithVideo(Video{ThumbnailURL: "https://example.com/t.jpg", Title: "Clip", Description: "A clip", PlayerURL: "https://example.com/player"}),
+	)
+	return sm
+}
+
+func TestImageSitemap(t *testing.T) {
+	data, err := mediaSitemap().ImageSitemap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml := string(data)
+
+	if got := urls(mustParse(t, data)); len(got) != 2 || got[0] != "https://example.com/gallery" || got[1] != "https://example.com/watch" {
+		t.Errorf("ImageSitemap() holds %v", got)
+	}
+	if !strings.Contains(xml, `xmlns:image="`+imageNamespace+`"`) {
+		t.Error("ImageSitemap() should declare the image namespace")
+	}
+	for _, other := range []string{"xmlns:video", "xmlns:news", "xmlns:xhtml", "<video:", "<news:", "<xhtml:"} {
+		if strings.Contains(xml, other) {
+			t.Errorf("ImageSitemap() should not contain %s", other)
+		}
+	}
+}
+
+func TestVideoSitemap(t *testing.T) {
+	data, err := mediaSitemap().VideoSitemap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	xml := string(data)
+
+	if got := urls(mustParse(t, data)); len(got) != 1 || got[0] != "https://example.com/watch" {
+		t.Errorf("VideoSitemap() holds %v", got)
+	}
+	if !strings.Contains(xml, `xmlns:video="`+videoNamespace+`"`) || strings.Contains(xml, "xmlns:image") {
+		t.Errorf("VideoSitemap() should declare only the video namespace:\n%s", xml)
+	}
+}
+
+func TestMediaSitemapsEmpty(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily)
+
+	if sm.ImageSitemaps() != nil || sm.VideoSitemaps() != nil {
+		t.Error("Expected no parts without media")
+	}
+	if data, err := sm.ImageSitemap(); err != nil || strings.Contains(string(data), "<url>") {
+		t.Errorf("ImageSitemap() should render an empty sitemap, got %s, %v", data, err)
+	}
+}
+
+func TestImageSitemapsPerURLLimit(t *testing.T) {
+	images := make([]Image, 2*maxImagesPerURL+500)
+	for i := range images {
+		images[i] = Image{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}
+	}
+
+	sm := New()
+	sm.Add("https://example.com/huge", time.Now(), 0.5, Daily, WithImages(images))
+	sm.Add("https://example.com/small", time.Now(), 0.5, Daily, WithImage(Image{URL: "https://example.com/s.jpg"}))
+
+	parts := sm.ImageSitemaps()
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts, got %d", len(parts))
+	}
+
+	total := 0
+	for i, part := range parts {
+		if !part.Has("https://example.com/huge") {
+			t.Errorf("Part %d should repeat the location with more images", i)
+		}
+		for _, item := range part.Items() {
+			if len(item.Images) > maxImagesPerURL {
+				t.Errorf("Part %d has %d images for one URL", i, len(item.Images))
+			}
+			if item.URL == "https://example.com/huge" {
+				total += len(item.Images)
+			}
+		}
+	}
+	if total != len(images) {
+		t.Errorf("Expected %d images across the parts, got %d", len(images), total)
+	}
+	if parts[0].Count() != 2 {
+		t.Errorf("The small URL should fit in the first part, got %d items", parts[0].Count())
+	}
+}
+
+func TestMediaSitemapsFileLimits(t *testing.T) {
+	sm := New()
+	for i := range 5 {
+		sm.Add(fmt.Sprintf("https://example.com/%d", i), time.Now(), 0.5, Daily,
+			WithImage(Image{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}))
+	}
+	sm.opts.MaxURLs = 2
+	if parts := sm.ImageSitemaps(); len(parts) != 3 || parts[2].Count() != 1 {
+		t.Errorf("Expected parts of 2, 2 and 1 URLs, got %d parts", len(parts))
+	}
+
+	sized := NewWithOptions(&Options{MaxBytes: headerAllowance + 600})
+	for i := range 4 {
+		sized.Add(fmt.Sprintf("https://example.com/%d", i), time.Now(), 0.5, Daily,
+			WithImage(Image{URL: fmt.Sprintf("https://example.com/%d.jpg", i)}))
+	}
+	parts := sized.ImageSitemaps()
+	if len(parts) < 2 {
+		t.Fatalf("Expected the size limit to split the sitemap, got %d parts", len(parts))
+	}
+	for i, part := range parts {
+		data, _ := part.XML()
+		if len(data) > headerAllowance+600 {
+			t.Errorf("Part %d is %d bytes", i, len(data))
+		}
+	}
+}
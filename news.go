@@ -0,0 +1,62 @@
+package sitemap
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Google News limits.
+const (
+	defaultNewsWindow   = 48 * time.Hour
+	maxNewsArticles     = 1000
+	maxNewsStockTickers = 5
+)
+
+// newsGenres are the values Google News accepted for genres.
+var newsGenres = []string{"PressRelease", "Satire", "Blog", "OpEd", "Opinion", "UserGenerated"}
+
+// NewsSitemaps returns the Google News sitemaps: the items with news
+// metadata published within Options.NewsWindow, newest first, split into
+// sitemaps of at most 1,000 articles. It returns nil if there are none.
+func (s *Sitemap) NewsSitemaps() []*Sitemap {
+	oldest := s.now().Add(-s.newsWindow())
+	news := s.Filter(func(item Item) bool {
+		return item.News != nil && !item.News.PublicationDate.Before(oldest)
+	})
+	slices.SortStableFunc(news.items, func(a, b Item) int {
+		return b.News.PublicationDate.Compare(a.News.PublicationDate)
+	})
+
+	var parts []*Sitemap
+	for chunk := range slices.Chunk(news.items, maxNewsArticles) {
+		parts = append(parts, s.derive(chunk))
+	}
+	return parts
+}
+
+// now returns the current time according to Options.Now.
+func (s *Sitemap) now() time.Time {
+	if s.opts.Now != nil {
+		return s.opts.Now()
+	}
+	return time.Now()
+}
+
+func (s *Sitemap) newsWindow() time.Duration {
+	if s.opts.NewsWindow > 0 {
+		return s.opts.NewsWindow
+	}
+	return defaultNewsWindow
+}
+
+// splitList splits a comma-separated news field.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,140 @@
+package sitemap
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewsWindow(t *testing.T) {
+	now := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	sm := NewWithOptions(&Options{Now: func() time.Time { return now }})
+
+	ages := map[string]time.Duration{
+		"fresh":   time.Hour,
+		"day":     24 * time.Hour,
+		"edge":    48 * time.Hour,
+		"stale":   49 * time.Hour,
+		"ancient": 30 * 24 * time.Hour,
+	}
+	for _, name := range []string{"day", "stale", "fresh", "ancient", "edge"} {
+		sm.Add("https://example.com/"+name, time.Time{}, 0, "", WithGoogleNews(GoogleNews{
+			SiteName:        "Example",
+			Language:        "en",
+			PublicationDate: now.Add(-ages[name]),
+			Title:           name,
+		}))
+	}
+	sm.Add("https://example.com/plain", time.Time{}, 0, "")
+
+	data, err := sm.GoogleNews()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := urls(mustParse(t, data))
+	expected := []string{"https://example.com/fresh", "https://example.com/day", "https://example.com/edge"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("GoogleNews() = %v, expected newest first %v", got, expected)
+	}
+
+	wide := NewWithOptions(&Options{Now: func() time.Time { return now }, NewsWindow: 7 * 24 * time.Hour})
+	wide.AddItems(sm.Items())
+	if parts := wide.NewsSitemaps(); len(parts) != 1 || parts[0].Count() != 4 {
+		t.Errorf("A 7 day window should include 4 articles, got %v", parts)
+	}
+
+	if f := findRule(sm.Validate(), RuleNewsAge); len(f) != 2 {
+		t.Errorf("Expected 2 news-age findings, got %v", f)
+	}
+}
+
+func TestNewsSitemapsSplit(t *testing.T) {
+	now := time.Now()
+	sm := NewWithOptions(&Options{MaxURLs: 2500})
+	for i := range 2500 {
+		sm.Add(fmt.Sprintf("https://example.com/%d", i), time.Time{}, 0, "", WithGoogleNews(GoogleNews{
+			SiteName:        "Example",
+			Language:        "en",
+			PublicationDate: now.Add(-time.Duration(i) * time.Second),
+			Title:           "Article",
+		}))
+	}
+
+	parts := sm.NewsSitemaps()
+	if len(parts) != 3 {
+		t.Fatalf("Expected 3 parts, got %d", len(parts))
+	}
+	for i, expected := range []int{1000, 1000, 500} {
+		if parts[i].Count() != expected {
+			t.Errorf("Part %d has %d articles, expected %d", i, parts[i].Count(), expected)
+		}
+	}
+	if parts[1].Items()[0].URL != "https://example.com/1000" {
+		t.Errorf("Parts should continue newest first, got %s", parts[1].Items()[0].URL)
+	}
+
+	data, _ := sm.GoogleNews()
+	if n := strings.Count(string(data), "<url>"); n != 1000 {
+		t.Errorf("GoogleNews() should hold the newest 1000 articles, got %d", n)
+	}
+
+	if New().NewsSitemaps() != nil {
+		t.Error("NewsSitemaps() should be nil without articles")
+	}
+}
+
+func TestNewsLegacyFields(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Time{}, 0, "", WithGoogleNews(GoogleNews{
+		SiteName:        "Example",
+		Language:        "en",
+		PublicationDate: time.Now(),
+		Title:           "Earnings",
+		Access:          "Subscription",
+		Genres:          "PressRelease, Blog",
+		StockTickers:    "NASDAQ:A, NASDAQ:B",
+	}))
+
+	data, _ := sm.GoogleNews()
+	for _, expected := range []string{
+		"<news:access>Subscription</news:access>",
+		"<news:genres>PressRelease, Blog</news:genres>",
+		"<news:stock_tickers>NASDAQ:A, NASDAQ:B</news:stock_tickers>",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("GoogleNews() should contain %s", expected)
+		}
+	}
+
+	news := mustParse(t, data).Items()[0].News
+	if news.Access != "Subscription" || news.Genres != "PressRelease, Blog" || news.StockTickers != "NASDAQ:A, NASDAQ:B" {
+		t.Errorf("Parse() lost the legacy fields: %+v", news)
+	}
+
+	if f := sm.Validate(); f != nil {
+		t.Errorf("Expected no findings, got %v", f)
+	}
+
+	sm.items[0].News.Access = "Free"
+	sm.items[0].News.Genres = "Blog, Gossip"
+	sm.items[0].News.StockTickers = "A, B, C, D, E, F"
+	findings := sm.Validate()
+	for _, rule := range []Rule{RuleNewsAccess, RuleNewsGenres, RuleNewsStockTickers} {
+		if len(findRule(findings, rule)) != 1 {
+			t.Errorf("Expected one %s finding, got %v", rule, findings)
+		}
+	}
+}
+
+func mustParse(t *testing.T, data []byte) *Sitemap {
+	t.Helper()
+	sm, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sm
+}
@@ -0,0 +1,122 @@
+package sitemap
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+)
+
+// TrailingSlash controls how a trailing slash in the URL path is normalized.
+type TrailingSlash int
+
+const (
+	// KeepTrailingSlash leaves the path as it is.
+	KeepTrailingSlash TrailingSlash = iota
+	// AddTrailingSlash appends a slash to paths that don't look like files.
+	AddTrailingSlash
+	// RemoveTrailingSlash strips the trailing slash from every path but "/".
+	RemoveTrailingSlash
+)
+
+// Normalization is a policy for canonicalizing sitemap locations.
+type Normalization struct {
+	Lowercase     bool          // lowercase the scheme and host
+	StripPort     bool          // remove :80 from http and :443 from https
+	TrailingSlash TrailingSlash // trailing slash policy for the path
+	StripFragment bool          // remove the #fragment
+	SortQuery     bool          // sort query parameters by name
+}
+
+// DuplicatePolicy decides what happens when a location is added twice.
+// Locations are compared after normalization.
+type DuplicatePolicy int
+
+const (
+	// AllowDuplicates adds every item, even if its location is already present.
+	AllowDuplicates DuplicatePolicy = iota
+	// KeepFirst ignores items whose location is already present.
+	KeepFirst
+	// KeepLast replaces the existing item in place.
+	KeepLast
+	// MergeDuplicates keeps the existing item, appends the images and videos
+	// it doesn't have yet and takes the newer LastMod.
+	MergeDuplicates
+)
+
+// normalizeURL applies the normalization policy to an absolute URL.
+func normalizeURL(rawURL string, n *Normalization) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", &URLError{URL: rawURL, Err: fmt.Errorf("%w: %w", ErrMalformedURL, err)}
+	}
+
+	if n.Lowercase {
+		u.Scheme = strings.ToLower(u.Scheme)
+		u.Host = strings.ToLower(u.Host)
+	}
+
+	if n.StripPort {
+		host, port, err := net.SplitHostPort(u.Host)
+		if err == nil && (u.Scheme == "http" && port == "80" || u.Scheme == "https" && port == "443") {
+			u.Host = host
+			if strings.Contains(host, ":") {
+				u.Host = "[" + host + "]"
+			}
+		}
+	}
+
+	switch n.TrailingSlash {
+	case AddTrailingSlash:
+		if !strings.HasSuffix(u.Path, "/") && !strings.Contains(path.Base(u.Path), ".") {
+			u.Path += "/"
+			u.RawPath = ""
+		}
+	case RemoveTrailingSlash:
+		if len(u.Path) > 1 && strings.HasSuffix(u.Path, "/") {
+			u.Path = strings.TrimRight(u.Path, "/")
+			u.RawPath = ""
+		}
+	}
+	if u.Path == "" && n.TrailingSlash != KeepTrailingSlash {
+		u.Path = "/"
+	}
+
+	if n.StripFragment {
+		u.Fragment = ""
+		u.RawFragment = ""
+	}
+
+	if n.SortQuery && u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+
+	return u.String(), nil
+}
+
+// mergeItem folds a duplicate item into an existing one.
+func mergeItem(existing *Item, dup Item) {
+	// Clip so appending never writes into a slice the caller still holds.
+	existing.Images = slices.Clip(existing.Images)
+	existing.Videos = slices.Clip(existing.Videos)
+
+	for _, img := range dup.Images {
+		if !slices.ContainsFunc(existing.Images, func(i Image) bool { return i.URL == img.URL }) {
+			existing.Images = append(existing.Images, img)
+		}
+	}
+
+	for _, video := range dup.Videos {
+		if !slices.ContainsFunc(existing.Videos, func(v Video) bool {
+			return v.ContentURL == video.ContentURL && v.PlayerURL == video.PlayerURL
+		}) {
+			existing.Videos = append(existing.Videos, video)
+		}
+	}
+
+	if dup.LastMod.After(existing.LastMod) {
+		existing.LastMod = dup.LastMod
+	}
+}
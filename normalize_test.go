@@ -0,0 +1,175 @@
+package sitemap
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	all := &Normalization{
+		Lowercase:     true,
+		StripPort:     true,
+		TrailingSlash: RemoveTrailingSlash,
+		StripFragment: true,
+		SortQuery:     true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   *Normalization
+		input    string
+		expected string
+	}{
+		{"lowercase", &Normalization{Lowercase: true}, "HTTP://Example.COM/Path", "http://example.com/Path"},
+		{"strip http port", &Normalization{StripPort: true}, "http://example.com:80/a", "http://example.com/a"},
+		{"strip https port", &Normalization{StripPort: true}, "https://example.com:443/a", "https://example.com/a"},
+		{"keep other port", &Normalization{StripPort: true}, "https://example.com:8443/a", "https://example.com:8443/a"},
+		{"keep mismatched port", &Normalization{StripPort: true}, "http://example.com:443/a", "http://example.com:443/a"},
+		{"strip ipv6 port", &Normalization{StripPort: true}, "http://[::1]:80/a", "http://[::1]/a"},
+		{"remove slash", &Normalization{TrailingSlash: RemoveTrailingSlash}, "https://example.com/a/", "https://example.com/a"},
+		{"remove slash keeps root", &Normalization{TrailingSlash: RemoveTrailingSlash}, "https://example.com/", "https://example.com/"},
+		{"empty path becomes root", &Normalization{TrailingSlash: RemoveTrailingSlash}, "https://example.com", "https://example.com/"},
+		{"add slash", &Normalization{TrailingSlash: AddTrailingSlash}, "https://example.com/a", "https://example.com/a/"},
+		{"add slash skips files", &Normalization{TrailingSlash: AddTrailingSlash}, "https://example.com/a.html", "https://example.com/a.html"},
+		{"keep slash", &Normalization{}, "https://example.com/a/", "https://example.com/a/"},
+		{"strip fragment", &Normalization{StripFragment: true}, "https://example.com/a#top", "https://example.com/a"},
+		{"sort query", &Normalization{SortQuery: true}, "https://example.com/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"all", all, "HTTP://Example.com:80/a/?z=1&y=2#frag", "http://example.com/a?y=2&z=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeURL(tt.input, tt.policy)
+			if err != nil {
+				t.Fatalf("normalizeURL() failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("normalizeURL(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeOnAdd(t *testing.T) {
+	sm := NewWithOptions(&Options{
+		Normalize: &Normalization{Lowercase: true, TrailingSlash: RemoveTrailingSlash},
+	})
+
+	if err := sm.Add("HTTPS://Example.com/a/", time.Now(), 0.5, Daily); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	if got := sm.Items()[0].URL; got != "https://example.com/a" {
+		t.Errorf("Expected normalized URL, got %s", got)
+	}
+}
+
+func TestDuplicatePolicies(t *testing.T) {
+	policy := &Normalization{Lowercase: true, TrailingSlash: RemoveTrailingSlash}
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.AddDate(0, 1, 0)
+
+	first := Item{
+		URL:     "HTTP://Example.com/a/",
+		LastMod: older,
+		Title:   "first",
+		Images:  []Image{{URL: "https://example.com/1.jpg"}},
+	}
+	second := Item{
+		URL:     "http://example.com/a",
+		LastMod: newer,
+		Title:   "second",
+		Images:  []Image{{URL: "https://example.com/1.jpg"}, {URL: "https://example.com/2.jpg"}},
+		Videos:  []Video{{ContentURL: "https://example.com/v.mp4"}},
+	}
+
+	t.Run("allow", func(t *testing.T) {
+		sm := NewWithOptions(&Options{Normalize: policy})
+		sm.AddItems([]Item{first, second})
+		if sm.Count() != 2 {
+			t.Errorf("Expected 2 items, got %d", sm.Count())
+		}
+	})
+
+	t.Run("keep first", func(t *testing.T) {
+		sm := NewWithOptions(&Options{Normalize: policy, Duplicates: KeepFirst})
+		if err := sm.AddItems([]Item{first, second}); err != nil {
+			t.Fatalf("AddItems() failed: %v", err)
+		}
+		if sm.Count() != 1 || sm.Items()[0].Title != "first" {
+			t.Errorf("Expected only the first item, got %+v", sm.Items())
+		}
+	})
+
+	t.Run("keep last", func(t *testing.T) {
+		sm := NewWithOptions(&Options{Normalize: policy, Duplicates: KeepLast})
+		sm.Add("https://example.com/other", older, 0.5, Daily)
+		if err := sm.AddItems([]Item{first, second}); err != nil {
+			t.Fatalf("AddItems() failed: %v", err)
+		}
+		if sm.Count() != 2 || sm.Items()[1].Title != "second" {
+			t.Errorf("Expected the last item in place, got %+v", sm.Items())
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		sm := NewWithOptions(&Options{Normalize: policy, Duplicates: MergeDuplicates})
+		if err := sm.AddItems([]Item{first, second}); err != nil {
+			t.Fatalf("AddItems() failed: %v", err)
+		}
+		if sm.Count() != 1 {
+			t.Fatalf("Expected 1 item, got %d", sm.Count())
+		}
+
+		item := sm.Items()[0]
+		if item.Title != "first" {
+			t.Errorf("Merged item should keep the first title, got %s", item.Title)
+		}
+		if len(item.Images) != 2 {
+			t.Errorf("Expected 2 distinct images, got %d", len(item.Images))
+		}
+		if len(item.Videos) != 1 {
+			t.Errorf("Expected 1 video, got %d", len(item.Videos))
+		}
+		if !item.LastMod.Equal(newer) {
+			t.Errorf("Merged item should take the newer LastMod, got %v", item.LastMod)
+		}
+		if len(first.Images) != 1 {
+			t.Error("Merging should not modify the caller's item")
+		}
+	})
+
+	t.Run("duplicates don't count toward MaxURLs", func(t *testing.T) {
+		sm := NewWithOptions(&Options{MaxURLs: 1, Duplicates: KeepFirst})
+		sm.Add("https://example.com/", time.Now(), 0.5, Daily)
+		if err := sm.Add("https://example.com/", time.Now(), 0.5, Daily); err != nil {
+			t.Errorf("Duplicate Add() should be ignored, got %v", err)
+		}
+	})
+
+	t.Run("clear resets duplicates", func(t *testing.T) {
+		sm := NewWithOptions(&Options{Duplicates: KeepFirst})
+		sm.Add("https://example.com/", time.Now(), 0.5, Daily)
+		sm.Clear()
+		sm.Add("https://example.com/", time.Now(), 0.5, Daily)
+		if sm.Count() != 1 {
+			t.Errorf("Expected 1 item after Clear(), got %d", sm.Count())
+		}
+	})
+}
+
+func TestWriterNormalize(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Normalize: &Normalization{StripFragment: true}})
+	w.Add("https://example.com/a#b", time.Time{}, 0.5, Daily)
+	w.Close()
+
+	sm, err := Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+	if got := sm.Items()[0].URL; got != "https://example.com/a" {
+		t.Errorf("Expected normalized URL, got %s", got)
+	}
+}
@@ -0,0 +1,100 @@
+package sitemap
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Filter returns a new sitemap with the items for which keep returns true,
+// in their original order. keep is given copies, so the receiver is not
+// modified.
+func (s *Sitemap) Filter(keep func(Item) bool) *Sitemap {
+	var items []Item
+	for _, item := range s.items {
+		if keep(cloneItem(item)) {
+			items = append(items, item)
+		}
+	}
+	return s.derive(items)
+}
+
+// Sort returns a new sitemap with the items sorted by compare, which
+// returns a negative number when a sorts before b, as in slices.SortFunc.
+// Equal items keep their order. ByLoc, ByLastMod and ByPriority cover the
+// common cases; wrap them in Descending to reverse the order.
+func (s *Sitemap) Sort(compare func(a, b Item) int) *Sitemap {
+	// Sort copies so compare can't reach the receiver's items.
+	items := s.Items()
+	slices.SortStableFunc(items, compare)
+	return s.derive(items)
+}
+
+// ByLoc orders items by location.
+func ByLoc(a, b Item) int {
+	return strings.Compare(a.URL, b.URL)
+}
+
+// ByLastMod orders items from the oldest to the newest modification.
+func ByLastMod(a, b Item) int {
+	return a.LastMod.Compare(b.LastMod)
+}
+
+// ByPriority orders items from the lowest to the highest priority. Items
+// without a priority sort as 0.5, the protocol default.
+func ByPriority(a, b Item) int {
+	return cmp.Compare(a.rankPriority(), b.rankPriority())
+}
+
+// Descending reverses an item ordering, so Descending(ByLastMod) puts the
+// newest items first.
+func Descending(compare func(a, b Item) int) func(a, b Item) int {
+	return func(a, b Item) int {
+		return compare(b, a)
+	}
+}
+
+// Merge returns a new sitemap with the receiver's options holding its items
+// followed by those of others. The other items are added as with AddItem,
+// so the duplicate and overflow policies apply. Items an eviction policy
+// drops for ranking too low are left out; any other item that can't be
+// added stops the merge.
+func (s *Sitemap) Merge(others ...*Sitemap) (*Sitemap, error) {
+	merged := s.clone()
+	for _, other := range others {
+		for _, item := range other.items {
+			if err := merged.add(item); err != nil && !errors.Is(err, ErrOutranked) {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+// PartitionBy splits the sitemap by the key of each item, for example its
+// section or host. key is given copies of the items. Each part has the
+// receiver's options and keeps the items in their original order.
+func (s *Sitemap) PartitionBy(key func(Item) string) map[string]*Sitemap {
+	groups := make(map[string][]Item)
+	for _, item := range s.items {
+		k := key(cloneItem(item))
+		groups[k] = append(groups[k], item)
+	}
+
+	parts := make(map[string]*Sitemap, len(groups))
+	for k, items := range groups {
+		parts[k] = s.derive(items)
+	}
+	return parts
+}
+
+// derive returns a sitemap with the receiver's options holding items.
+func (s *Sitemap) derive(items []Item) *Sitemap {
+	if items == nil {
+		items = make([]Item, 0)
+	}
+	d := &Sitemap{items: items, opts: s.opts}
+	d.reindex()
+	return d
+}
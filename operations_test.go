@@ -0,0 +1,184 @@
+package sitemap
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// urls returns the locations of the items in order.
+func urls(sm *Sitemap) []string {
+	var out []string
+	for _, item := range sm.Items() {
+		out = append(out, item.URL)
+	}
+	return out
+}
+
+func TestFilter(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.9, Daily)
+	sm.Add("https://example.com/b", time.Now(), 0.1, Daily)
+	sm.Add("https://example.com/c", time.Now(), 0.8, Daily)
+
+	important := sm.Filter(func(item Item) bool { return item.Priority >= 0.5 })
+
+	if got := urls(important); !slices.Equal(got, []string{"https://example.com/a", "https://example.com/c"}) {
+		t.Errorf("Filter() = %v", got)
+	}
+	if sm.Count() != 3 {
+		t.Error("Filter() should not modify the receiver")
+	}
+	if !important.Has("https://example.com/c") {
+		t.Error("Filtered sitemaps should support lookups")
+	}
+
+	none := sm.Filter(func(Item) bool { return false })
+	if data, err := none.XML(); err != nil || !strings.Contains(string(data), "<urlset") {
+		t.Errorf("An empty filtered sitemap should render, got %s, %v", data, err)
+	}
+}
+
+func TestSort(t *testing.T) {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sm := New()
+	sm.Add("https://example.com/b", base.AddDate(0, 0, 1), 0.5, Daily)
+	sm.Add("https://example.com/c", base, 0.9, Daily)
+	sm.Add("https://example.com/a", base.AddDate(0, 0, 2), 0.5, Daily)
+
+	tests := []struct {
+		name     string
+		compare  func(a, b Item) int
+		expected []string
+	}{
+		{"loc", ByLoc, []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}},
+		{"lastmod", ByLastMod, []string{"https://example.com/c", "https://example.com/b", "https://example.com/a"}},
+		{"newest first", Descending(ByLastMod), []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}},
+		// Equal priorities keep their order.
+		{"priority", ByPriority, []string{"https://example.com/b", "https://example.com/a", "https://example.com/c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := urls(sm.Sort(tt.compare)); !slices.Equal(got, tt.expected) {
+				t.Errorf("Sort() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+
+	if got := urls(sm); got[0] != "https://example.com/b" {
+		t.Error("Sort() should not modify the receiver")
+	}
+
+	// A missing priority sorts as the protocol default of 0.5.
+	sm = New()
+	sm.Add("https://example.com/unset", base, 0, Daily)
+	sm.Add("https://example.com/high", base, 0.6, Daily)
+	sm.Add("https://example.com/zero", base, 0, Daily, WithPriority(0))
+	sm.Add("https://example.com/low", base, 0.1, Daily)
+	expected := []string{"https://example.com/zero", "https://example.com/low", "https://example.com/unset", "https://example.com/high"}
+	if got := urls(sm.Sort(ByPriority)); !slices.Equal(got, expected) {
+		t.Errorf("Sort(ByPriority) = %v, expected %v", got, expected)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := NewWithOptions(&Options{Duplicates: KeepFirst})
+	a.Add("https://example.com/1", time.Now(), 0.5, Daily)
+	a.Add("https://example.com/2", time.Now(), 0.5, Daily)
+
+	b := New()
+	b.Add("https://example.com/2", time.Now(), 0.9, Daily)
+	b.Add("https://example.com/3", time.Now(), 0.5, Daily)
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge() failed: %v", err)
+	}
+	if got := urls(merged); !slices.Equal(got, []string{"https://example.com/1", "https://example.com/2", "https://example.com/3"}) {
+		t.Errorf("Merge() = %v", got)
+	}
+	if item, _ := merged.Get("https://example.com/2"); item.Priority != 0.5 {
+		t.Error("Merge() should apply the receiver's duplicate policy")
+	}
+	if a.Count() != 2 {
+		t.Error("Merge() should not modify the receiver")
+	}
+
+	small := NewWithOptions(&Options{MaxURLs: 2})
+	small.Add("https://example.com/1", time.Now(), 0.5, Daily)
+	if _, err := small.Merge(b); !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Expected ErrLimitReached, got %v", err)
+	}
+}
+
+func TestMergeEviction(t *testing.T) {
+	a := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority})
+	a.Add("https://example.com/1", time.Now(), 0.5, Daily)
+	a.Add("https://example.com/2", time.Now(), 0.3, Daily)
+
+	b := New()
+	b.Add("https://example.com/low", time.Now(), 0.1, Daily)
+	b.Add("https://example.com/high", time.Now(), 0.9, Daily)
+
+	merged, err := a.Merge(b)
+	if err != nil {
+		t.Fatalf("Merge() should drop outranked items, got %v", err)
+	}
+	if got := locs(merged.Items()); !slices.Equal(got, []string{"https://example.com/1", "https://example.com/high"}) {
+		t.Errorf("Merge() = %v", got)
+	}
+	if a.Count() != 2 || !a.Has("https://example.com/2") {
+		t.Error("Merge() should not modify the receiver")
+	}
+}
+
+func TestPartitionBy(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/blog/1", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/shop/1", time.Now(), 0.5, Daily)
+	sm.Add("https://example.com/blog/2", time.Now(), 0.5, Daily)
+
+	parts := sm.PartitionBy(func(item Item) string {
+		return strings.Split(item.URL, "/")[3]
+	})
+
+	if len(parts) != 2 {
+		t.Fatalf("Expected 2 parts, got %d", len(parts))
+	}
+	if got := urls(parts["blog"]); !slices.Equal(got, []string{"https://example.com/blog/1", "https://example.com/blog/2"}) {
+		t.Errorf("blog part = %v", got)
+	}
+	if parts["shop"].Count() != 1 {
+		t.Errorf("shop part has %d items", parts["shop"].Count())
+	}
+}
+
+func TestCallbacksGetCopies(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily,
+		WithImage(Image{URL: "https://example.com/a.jpg"}),
+		WithTranslation(Translation{Language: "de", URL: "https://example.com/de"}))
+	frozen := sm.Freeze()
+
+	tamper := func(item Item) {
+		item.Images[0].URL = "https://evil.example/"
+		item.Langs[0].URL = "https://evil.example/"
+	}
+
+	sm.Filter(func(item Item) bool { tamper(item); return true })
+	sm.PartitionBy(func(item Item) string { tamper(item); return "" })
+	sm.Add("https://example.com/b", time.Now(), 0.5, Daily, WithImage(Image{URL: "https://example.com/b.jpg"}),
+		WithTranslation(Translation{Language: "de", URL: "https://example.com/de"}))
+	sm.Sort(func(a, b Item) int { tamper(a); tamper(b); return ByLoc(a, b) })
+
+	for _, s := range []interface{ Items() []Item }{sm, frozen} {
+		for _, item := range s.Items() {
+			if strings.Contains(item.Images[0].URL, "evil") || strings.Contains(item.Langs[0].URL, "evil") {
+				t.Errorf("A callback changed the stored item %s", item.URL)
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package sitemap
+
+import (
+	"container/heap"
+	"fmt"
+)
+
+// OverflowPolicy decides what happens when an item is added to a sitemap
+// that already holds Options.MaxURLs items.
+type OverflowPolicy int
+
+const (
+	// RejectOverflow returns ErrLimitReached.
+	RejectOverflow OverflowPolicy = iota
+	// EvictLowestPriority replaces the item with the lowest priority, the
+	// oldest LastMod breaking ties, if the new item ranks higher, and
+	// returns ErrOutranked otherwise. Items without a priority rank as 0.5,
+	// the protocol default.
+	EvictLowestPriority
+	// EvictOldest replaces the item with the oldest LastMod, the lowest
+	// priority breaking ties, if the new item ranks higher, and returns
+	// ErrOutranked otherwise.
+	EvictOldest
+)
+
+// worse reports whether a ranks below b under the policy.
+func (p OverflowPolicy) worse(a, b *Item) bool {
+	if p == EvictOldest {
+		if !a.LastMod.Equal(b.LastMod) {
+			return a.LastMod.Before(b.LastMod)
+		}
+		return a.rankPriority() < b.rankPriority()
+	}
+	if pa, pb := a.rankPriority(), b.rankPriority(); pa != pb {
+		return pa < pb
+	}
+	return a.LastMod.Before(b.LastMod)
+}
+
+// rankPriority returns the priority used to rank the item: its own if set,
+// otherwise the protocol default of 0.5.
+func (i *Item) rankPriority() float64 {
+	if !i.hasPriority() {
+		return defaultPriority
+	}
+	return i.Priority
+}
+
+// rankHeap orders the indexes of a full sitemap's items with the lowest
+// ranked item on top. pos maps an item index to its position in the heap so
+// items changed in place can be fixed up.
+type rankHeap struct {
+	s   *Sitemap
+	idx []int
+	pos []int
+}
+
+func newRankHeap(s *Sitemap) *rankHeap {
+	h := &rankHeap{
+		s:   s,
+		idx: make([]int, len(s.items)),
+		pos: make([]int, len(s.items)),
+	}
+	for i := range s.items {
+		h.idx[i] = i
+		h.pos[i] = i
+	}
+	heap.Init(h)
+	return h
+}
+
+func (h *rankHeap) Len() int { return len(h.idx) }
+
+func (h *rankHeap) Less(i, j int) bool {
+	return h.s.opts.Overflow.worse(&h.s.items[h.idx[i]], &h.s.items[h.idx[j]])
+}
+
+func (h *rankHeap) Swap(i, j int) {
+	h.idx[i], h.idx[j] = h.idx[j], h.idx[i]
+	h.pos[h.idx[i]] = i
+	h.pos[h.idx[j]] = j
+}
+
+// Push and Pop are never called; the heap keeps a fixed size.
+func (h *rankHeap) Push(any) { panic("sitemap: rankHeap.Push") }
+func (h *rankHeap) Pop() any { panic("sitemap: rankHeap.Pop") }
+
+// evict puts item in place of the lowest ranked item of a full sitemap if
+// it ranks higher, and returns ErrOutranked otherwise.
+func (s *Sitemap) evict(item Item) error {
+	if s.ranks == nil {
+		s.ranks = newRankHeap(s)
+	}
+
+	i := s.ranks.idx[0]
+	if !s.opts.Overflow.worse(&s.items[i], &item) {
+		return fmt.Errorf("%w %q", ErrOutranked, item.URL)
+	}
+
+	old := s.items[i].URL
+	s.items[i] = item
+	heap.Fix(s.ranks, 0)
+	s.rekey(i, old)
+	return nil
+}
+
+// changed restores the eviction order after item i was modified in place.
+func (s *Sitemap) changed(i int) {
+	if s.ranks != nil {
+		heap.Fix(s.ranks, s.ranks.pos[i])
+	}
+}
@@ -0,0 +1,177 @@
+package sitemap
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+// locs returns the locations of the items, sorted.
+func locs(items []Item) []string {
+	out := make([]string, len(items))
+	for i, item := range items {
+		out[i] = item.URL
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestOverflowReject(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 1})
+	sm.Add("https://example.com/1", time.Now(), 0.1, Daily)
+
+	err := sm.Add("https://example.com/2", time.Now(), 1.0, Daily)
+	if !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Expected ErrLimitReached, got %v", err)
+	}
+}
+
+func TestOverflowEvictLowestPriority(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 10, Overflow: EvictLowestPriority})
+
+	// Priorities 0.1 to 1.0 twice over, in random order.
+	var items []Item
+	for i := range 20 {
+		items = append(items, Item{URL: fmt.Sprintf("https://example.com/%02d", i), Priority: float64(i%10+1) / 10})
+	}
+	rand.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+
+	// Items that rank below the whole sitemap are dropped and reported.
+	var batchErr *BatchError
+	if err := sm.AddBatch(items, BatchBestEffort); err != nil && !errors.As(err, &batchErr) {
+		t.Fatalf("AddBatch() failed: %v", err)
+	}
+	if batchErr != nil {
+		for _, r := range batchErr.Rejected {
+			if !errors.Is(r.Err, ErrOutranked) {
+				t.Errorf("Item %d rejected with %v, expected ErrOutranked", r.Index, r.Err)
+			}
+		}
+	}
+
+	if sm.Count() != 10 {
+		t.Fatalf("Expected 10 items, got %d", sm.Count())
+	}
+	for _, item := range sm.Items() {
+		if item.Priority < 0.5 {
+			t.Errorf("Item %s with priority %.1f should have been evicted", item.URL, item.Priority)
+		}
+	}
+}
+
+func TestOverflowUnsetPriority(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority})
+
+	// Without a priority an item ranks as 0.5, above an explicit 0.1 or 0.
+	sm.Add("https://example.com/unset", time.Time{}, 0, Daily)
+	sm.Add("https://example.com/low", time.Time{}, 0.1, Daily)
+	sm.Add("https://example.com/zero", time.Time{}, 0, Daily, WithPriority(0))
+	sm.Add("https://example.com/high", time.Time{}, 0.6, Daily)
+
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/high", "https://example.com/unset"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+}
+
+func TestOverflowOutranked(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority})
+	sm.Add("https://example.com/a", time.Time{}, 0.5, Daily)
+	sm.Add("https://example.com/b", time.Time{}, 0.5, Daily)
+
+	err := sm.Add("https://example.com/low", time.Time{}, 0.1, Daily)
+	if !errors.Is(err, ErrOutranked) {
+		t.Errorf("Expected ErrOutranked, got %v", err)
+	}
+
+	err = sm.AddBatch([]Item{
+		{URL: "https://example.com/low", Priority: 0.1},
+		{URL: "https://example.com/high", Priority: 0.9},
+	}, BatchBestEffort)
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("Expected a *BatchError, got %v", err)
+	}
+	if batchErr.Added != 1 || len(batchErr.Rejected) != 1 || batchErr.Rejected[0].Index != 0 || !errors.Is(err, ErrOutranked) {
+		t.Errorf("Expected item 0 rejected as outranked and 1 added, got %v", err)
+	}
+
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/b", "https://example.com/high"}) &&
+		!slices.Equal(got, []string{"https://example.com/a", "https://example.com/high"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+}
+
+func TestOverflowEvictOldest(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictOldest})
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	sm.Add("https://example.com/b", base.AddDate(0, 0, 2), 0.1, Daily)
+	sm.Add("https://example.com/a", base.AddDate(0, 0, 1), 1.0, Daily)
+	sm.Add("https://example.com/c", base.AddDate(0, 0, 3), 0.1, Daily)
+	sm.Add("https://example.com/old", base, 1.0, Daily)
+
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/b", "https://example.com/c"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+
+	// Ties on LastMod fall back to priority.
+	sm.Add("https://example.com/d", base.AddDate(0, 0, 2), 0.5, Daily)
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/c", "https://example.com/d"}) {
+		t.Errorf("Unexpected items after tie %v", got)
+	}
+}
+
+func TestOverflowWithDuplicates(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority, Duplicates: KeepLast})
+
+	sm.Add("https://example.com/a", time.Time{}, 0.2, Daily)
+	sm.Add("https://example.com/b", time.Time{}, 0.5, Daily)
+	sm.Add("https://example.com/c", time.Time{}, 0.3, Daily) // evicts a
+
+	// Lowering b in place makes it the next to go.
+	sm.Add("https://example.com/b", time.Time{}, 0.1, Daily)
+	sm.Add("https://example.com/a", time.Time{}, 0.2, Daily) // evicts b
+
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/a", "https://example.com/c"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+
+	// The evicted location can be added again.
+	sm.Add("https://example.com/b", time.Time{}, 0.9, Daily)
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/b", "https://example.com/c"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+
+	sm.Clear()
+	sm.Add("https://example.com/x", time.Time{}, 0.1, Daily)
+	sm.Add("https://example.com/y", time.Time{}, 0.15, Daily)
+	sm.Add("https://example.com/z", time.Time{}, 0.2, Daily)
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/y", "https://example.com/z"}) {
+		t.Errorf("Unexpected items after Clear %v", got)
+	}
+}
+
+func TestOverflowAllOrNothingBatch(t *testing.T) {
+	sm := NewWithOptions(&Options{MaxURLs: 2, Overflow: EvictLowestPriority})
+
+	err := sm.AddBatch([]Item{
+		{URL: "https://example.com/a", Priority: 0.2},
+		{URL: "https://example.com/b", Priority: 0.3},
+		{URL: "https://example.com/c", Priority: 0.4}, // evicts a
+	}, BatchAllOrNothing)
+	if err != nil {
+		t.Fatalf("AddBatch() failed: %v", err)
+	}
+	if sm.ranks == nil || sm.ranks.s != sm {
+		t.Fatal("The eviction heap should belong to the sitemap after the batch")
+	}
+
+	// Evictions after the batch use the sitemap's own items.
+	sm.Add("https://example.com/d", time.Time{}, 0.5, Daily) // evicts b
+	if got := locs(sm.Items()); !slices.Equal(got, []string{"https://example.com/c", "https://example.com/d"}) {
+		t.Errorf("Unexpected items %v", got)
+	}
+}
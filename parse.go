@@ -0,0 +1,325 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The parse types match elements by local name only, so documents that use
+// other prefixes or leave an extension namespace undeclared still decode.
+
+type parseURLSet struct {
+	XMLName xml.Name   `xml:"urlset"`
+	URLs    []parseURL `xml:"url"`
+}
+
+type parseURL struct {
+	Loc        string       `xml:"loc"`
+	LastMod    string       `xml:"lastmod"`
+	ChangeFreq string       `xml:"changefreq"`
+	Priority   string       `xml:"priority"`
+	Images     []parseImage `xml:"image"`
+	Videos     []parseVideo `xml:"video"`
+	News       *parseNews   `xml:"news"`
+	Links      []parseLink  `xml:"link"`
+}
+
+type parseImage struct {
+	Loc     string `xml:"loc"`
+	Title   string `xml:"title"`
+	Caption string `xml:"caption"`
+}
+
+type parseVideo struct {
+	ThumbnailLoc         string                  `xml:"thumbnail_loc"`
+	Title                string                  `xml:"title"`
+	Description          string                  `xml:"description"`
+	ContentLoc           string                  `xml:"content_loc"`
+	PlayerLoc            string                  `xml:"player_loc"`
+	Duration             string                  `xml:"duration"`
+	ExpirationDate       string                  `xml:"expiration_date"`
+	Rating               string                  `xml:"rating"`
+	ViewCount            string                  `xml:"view_count"`
+	PublicationDate      string                  `xml:"publication_date"`
+	FamilyFriendly       string                  `xml:"family_friendly"`
+	Restriction          *parseVideoRelationship `xml:"restriction"`
+	Platform             *parseVideoRelationship `xml:"platform"`
+	Prices               []parseVideoPrice       `xml:"price"`
+	RequiresSubscription string                  `xml:"requires_subscription"`
+	Uploader             *parseVideoUploader     `xml:"uploader"`
+	Live                 string                  `xml:"live"`
+	Tags                 []string                `xml:"tag"`
+}
+
+type parseVideoRelationship struct {
+	Relationship string `xml:"relationship,attr"`
+	Values       string `xml:",chardata"`
+}
+
+type parseVideoPrice struct {
+	Currency   string `xml:"currency,attr"`
+	Type       string `xml:"type,attr"`
+	Resolution string `xml:"resolution,attr"`
+	Amount     string `xml:",chardata"`
+}
+
+type parseVideoUploader struct {
+	Info string `xml:"info,attr"`
+	Name string `xml:",chardata"`
+}
+
+type parseNews struct {
+	Name            string `xml:"publication>name"`
+	Language        string `xml:"publication>language"`
+	PublicationDate string `xml:"publication_date"`
+	Title           string `xml:"title"`
+	Keywords        string `xml:"keywords"`
+	Access          string `xml:"access"`
+	Genres          string `xml:"genres"`
+	StockTickers    string `xml:"stock_tickers"`
+}
+
+type parseLink struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Media    string `xml:"media,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+type parseSitemapIndex struct {
+	XMLName  xml.Name            `xml:"sitemapindex"`
+	Sitemaps []parseIndexSitemap `xml:"sitemap"`
+}
+
+type parseIndexSitemap struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// Parse reads a sitemap XML document, including the image, video, news and
+// xhtml:link extensions, back into a Sitemap.
+//
+// Parse does not apply the checks performed by Add, so sitemaps that are
+// already published can be loaded as they are for auditing or merging.
+func Parse(r io.Reader) (*Sitemap, error) {
+	var urlset parseURLSet
+	if err := xml.NewDecoder(r).Decode(&urlset); err != nil {
+		return nil, fmt.Errorf("parsing sitemap: %w", err)
+	}
+
+	sm := New()
+	if len(urlset.URLs) > sm.opts.MaxURLs {
+		sm.opts.MaxURLs = len(urlset.URLs)
+	}
+
+	for i, u := range urlset.URLs {
+		item, err := u.item()
+		if err != nil {
+			return nil, fmt.Errorf("parsing sitemap url %d: %w", i, err)
+		}
+		sm.items = append(sm.items, item)
+	}
+	sm.reindex()
+
+	return sm, nil
+}
+
+// ParseIndex reads a sitemap index XML document back into an Index.
+func ParseIndex(r io.Reader) (*Index, error) {
+	var index parseSitemapIndex
+	if err := xml.NewDecoder(r).Decode(&index); err != nil {
+		return nil, fmt.Errorf("parsing sitemap index: %w", err)
+	}
+
+	idx := NewIndex()
+	for i, s := range index.Sitemaps {
+		lastMod, err := parseDate(s.LastMod)
+		if err != nil {
+			return nil, fmt.Errorf("parsing sitemap index entry %d: lastmod: %w", i, err)
+		}
+
+		idx.sitemaps = append(idx.sitemaps, IndexItem{
+			URL:     strings.TrimSpace(s.Loc),
+			LastMod: lastMod,
+		})
+	}
+
+	return idx, nil
+}
+
+// item converts a decoded url element into an Item.
+func (u parseURL) item() (Item, error) {
+	item := Item{
+		URL:        strings.TrimSpace(u.Loc),
+		ChangeFreq: ChangeFreq(strings.TrimSpace(u.ChangeFreq)),
+	}
+
+	var err error
+	if item.LastMod, err = parseDate(u.LastMod); err != nil {
+		return Item{}, fmt.Errorf("lastmod: %w", err)
+	}
+
+	if p := strings.TrimSpace(u.Priority); p != "" {
+		if item.Priority, err = strconv.ParseFloat(p, 64); err != nil {
+			return Item{}, fmt.Errorf("priority: %w", err)
+		}
+		// Only an explicit 0.0 needs marking to be written again.
+		item.PrioritySet = item.Priority == 0
+	}
+
+	for _, img := range u.Images {
+		item.Images = append(item.Images, Image{
+			URL:     strings.TrimSpace(img.Loc),
+			Title:   img.Title,
+			Caption: img.Caption,
+		})
+	}
+
+	for _, v := range u.Videos {
+		video, err := v.video()
+		if err != nil {
+			return Item{}, err
+		}
+		item.Videos = append(item.Videos, video)
+	}
+
+	if u.News != nil {
+		pubDate, err := parseDate(u.News.PublicationDate)
+		if err != nil {
+			return Item{}, fmt.Errorf("news publication_date: %w", err)
+		}
+		item.News = &GoogleNews{
+			SiteName:        u.News.Name,
+			Language:        strings.TrimSpace(u.News.Language),
+			PublicationDate: pubDate,
+			Title:           u.News.Title,
+			Keywords:        u.News.Keywords,
+			Access:          strings.TrimSpace(u.News.Access),
+			Genres:          strings.TrimSpace(u.News.Genres),
+			StockTickers:    strings.TrimSpace(u.News.StockTickers),
+		}
+	}
+
+	for _, link := range u.Links {
+		if link.Rel != "alternate" {
+			continue
+		}
+		if link.Hreflang != "" {
+			item.Langs = append(item.Langs, Translation{
+				Language: link.Hreflang,
+				URL:      link.Href,
+			})
+		} else {
+			item.Alternates = append(item.Alternates, Alternate{
+				Media: link.Media,
+				URL:   link.Href,
+			})
+		}
+	}
+
+	return item, nil
+}
+
+// video converts a decoded video element into a Video.
+func (v parseVideo) video() (Video, error) {
+	video := Video{
+		ThumbnailURL:         strings.TrimSpace(v.ThumbnailLoc),
+		Title:                v.Title,
+		Description:          v.Description,
+		ContentURL:           strings.TrimSpace(v.ContentLoc),
+		PlayerURL:            strings.TrimSpace(v.PlayerLoc),
+		FamilyFriendly:       YesNo(strings.TrimSpace(v.FamilyFriendly)),
+		RequiresSubscription: YesNo(strings.TrimSpace(v.RequiresSubscription)),
+		Live:                 YesNo(strings.TrimSpace(v.Live)),
+	}
+
+	var err error
+	if d := strings.TrimSpace(v.Duration); d != "" {
+		if video.Duration, err = strconv.Atoi(d); err != nil {
+			return Video{}, fmt.Errorf("video duration: %w", err)
+		}
+	}
+	if video.ExpirationDate, err = parseDate(v.ExpirationDate); err != nil {
+		return Video{}, fmt.Errorf("video expiration_date: %w", err)
+	}
+	if r := strings.TrimSpace(v.Rating); r != "" {
+		if video.Rating, err = strconv.ParseFloat(r, 64); err != nil {
+			return Video{}, fmt.Errorf("video rating: %w", err)
+		}
+	}
+	if c := strings.TrimSpace(v.ViewCount); c != "" {
+		if video.ViewCount, err = strconv.Atoi(c); err != nil {
+			return Video{}, fmt.Errorf("video view_count: %w", err)
+		}
+	}
+	if video.PublicationDate, err = parseDate(v.PublicationDate); err != nil {
+		return Video{}, fmt.Errorf("video publication_date: %w", err)
+	}
+
+	if r := v.Restriction; r != nil {
+		video.Restriction = &VideoRestriction{
+			Relationship: Relationship(strings.TrimSpace(r.Relationship)),
+			Countries:    strings.Fields(r.Values),
+		}
+	}
+	if p := v.Platform; p != nil {
+		video.Platform = &VideoPlatform{
+			Relationship: Relationship(strings.TrimSpace(p.Relationship)),
+			Platforms:    strings.Fields(p.Values),
+		}
+	}
+
+	for _, p := range v.Prices {
+		price := VideoPrice{
+			Currency:   strings.TrimSpace(p.Currency),
+			Type:       strings.TrimSpace(p.Type),
+			Resolution: strings.TrimSpace(p.Resolution),
+		}
+		if price.Amount, err = strconv.ParseFloat(strings.TrimSpace(p.Amount), 64); err != nil {
+			return Video{}, fmt.Errorf("video price: %w", err)
+		}
+		video.Prices = append(video.Prices, price)
+	}
+
+	if u := v.Uploader; u != nil {
+		video.Uploader = &VideoUploader{
+			Name: strings.TrimSpace(u.Name),
+			Info: strings.TrimSpace(u.Info),
+		}
+	}
+
+	for _, tag := range v.Tags {
+		video.Tags = append(video.Tags, strings.TrimSpace(tag))
+	}
+
+	return video, nil
+}
+
+// dateLayouts are the W3C Datetime profiles accepted by the protocol.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// parseDate parses a W3C Datetime value. An empty value yields the zero time.
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid W3C datetime %q", value)
+}
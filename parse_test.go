@@ -0,0 +1,170 @@
+package sitemap
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	sm := New()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	err := sm.Add(
+		"https://example.com/article",
+		now,
+		0.8,
+		Weekly,
+		WithImages([]Image{
+			{URL: "https://example.com/image1.jpg", Title: "Image 1", Caption: "First image"},
+			{URL: "https://example.com/image2.jpg"},
+		}),
+		WithVideos([]Video{{
+			ThumbnailURL: "https://example.com/thumb.jpg",
+			Title:        "Video",
+			Description:  "A video",
+			ContentURL:   "https://example.com/video.mp4",
+			PlayerURL:    "https://example.com/player",
+			Duration:     120,
+		}}),
+		WithGoogleNews(GoogleNews{
+			SiteName:        "Example News",
+			Language:        "en",
+			PublicationDate: now,
+			Title:           "Headline",
+			Keywords:        "one, two",
+		}),
+		WithAlternates([]Alternate{{Media: "only screen and (max-width: 640px)", URL: "https://m.example.com/article"}}),
+		WithTranslations([]Translation{{Language: "de", URL: "https://example.com/de/article"}}),
+	)
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	sm.Add("https://example.com/", time.Time{}, 1.0, "")
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed.Items(), sm.Items()) {
+		t.Errorf("Parsed items differ from original\ngot:      %+v\nexpected: %+v", parsed.Items(), sm.Items())
+	}
+}
+
+func TestParseForeignPrefixes(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"
+        xmlns:img="http://www.google.com/schemas/sitemap-image/1.1">
+  <url>
+    <loc>
+      https://example.com/page
+    </loc>
+    <lastmod>2024-01-02</lastmod>
+    <priority>0.5</priority>
+    <img:image><img:loc>https://example.com/a.jpg</img:loc></img:image>
+  </url>
+</urlset>`
+
+	sm, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("Parse() failed: %v", err)
+	}
+
+	if sm.Count() != 1 {
+		t.Fatalf("Expected 1 item, got %d", sm.Count())
+	}
+
+	item := sm.Items()[0]
+	if item.URL != "https://example.com/page" {
+		t.Errorf("Expected trimmed loc, got %q", item.URL)
+	}
+	if !item.LastMod.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected lastmod %v", item.LastMod)
+	}
+	if item.Priority != 0.5 {
+		t.Errorf("Expected priority 0.5, got %f", item.Priority)
+	}
+	if len(item.Images) != 1 || item.Images[0].URL != "https://example.com/a.jpg" {
+		t.Errorf("Unexpected images %+v", item.Images)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+	}{
+		{"not xml", "not xml"},
+		{"wrong root", `<sitemapindex><sitemap><loc>https://example.com/</loc></sitemap></sitemapindex>`},
+		{"bad lastmod", `<urlset><url><loc>https://example.com/</loc><lastmod>yesterday</lastmod></url></urlset>`},
+		{"bad priority", `<urlset><url><loc>https://example.com/</loc><priority>high</priority></url></urlset>`},
+		{"bad duration", `<urlset><url><loc>https://example.com/</loc><video><duration>long</duration></video></url></urlset>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.doc)); err == nil {
+				t.Error("Parse() should have failed")
+			}
+		})
+	}
+}
+
+func TestParseIndexRoundTrip(t *testing.T) {
+	idx := NewIndex()
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	idx.Add("https://example.com/sitemap-1.xml", now)
+	idx.Add("https://example.com/sitemap-2.xml", time.Time{})
+
+	data, err := idx.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	parsed, err := ParseIndex(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ParseIndex() failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(parsed.sitemaps, idx.sitemaps) {
+		t.Errorf("Parsed index differs from original\ngot:      %+v\nexpected: %+v", parsed.sitemaps, idx.sitemaps)
+	}
+
+	if _, err := ParseIndex(strings.NewReader(`<urlset></urlset>`)); err == nil {
+		t.Error("ParseIndex() should fail for a urlset document")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"", time.Time{}},
+		{"2024", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-04", time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{"2024-03-04T05:06Z", time.Date(2024, 3, 4, 5, 6, 0, 0, time.UTC)},
+		{"2024-03-04T05:06:07Z", time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"2024-03-04T05:06:07.5+00:00", time.Date(2024, 3, 4, 5, 6, 7, 500000000, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseDate(tt.value)
+		if err != nil {
+			t.Errorf("parseDate(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("parseDate(%q) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
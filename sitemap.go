@@ -7,12 +7,17 @@
 package sitemap
 
 import (
+	"encoding/json"
 	"fmt"
+	"iter"
+	"maps"
 	"net/url"
+	"slices"
 	"time"
 )
 
-// ChangeFreq represents how frequently the page is likely to change.
+// ChangeFreq represents how frequently the page is likely to change. The
+// empty value leaves changefreq out of the sitemap.
 type ChangeFreq string
 
 const (
@@ -25,31 +30,142 @@
 	Never   ChangeFreq = "never"
 )
 
+// Protocol limits for a single sitemap file.
+const (
+	defaultMaxURLs  = 50000
+	defaultMaxBytes = 50 * 1024 * 1024
+)
+
+// defaultPriority is the priority the protocol assumes for a URL without
+// one.
+const defaultPriority = 0.5
+
 // Sitemap represents a sitemap that can contain multiple URLs with their metadata.
 type Sitemap struct {
 	items []Item
 	opts  Options
+	byLoc map[string]int // location -> index of its first item
+	dups  int            // items sharing their location with an earlier item
+	ranks *rankHeap      // eviction order, built once the sitemap is full
 }
 
 // Options contains configuration options for the sitemap.
 type Options struct {
-	MaxURLs     int
-	BaseURL     string
+	MaxURLs int
+
+	// BaseURL is used to resolve relative locations, including image,
+	// video, alternate and translation URLs. Without it relative locations
+	// are rejected.
+	BaseURL string
+
 	PreAllocate bool
+
+	// MaxBytes limits the uncompressed size of a document produced by a
+	// Writer or split off by ImageSitemaps and VideoSitemaps. Zero uses the
+	// protocol limit of 50MB.
+	MaxBytes int64
+
+	// Normalize canonicalizes every location before it is added. Nil
+	// leaves locations untouched.
+	Normalize *Normalization
+
+	// Duplicates decides what happens when a location is added twice.
+	Duplicates DuplicatePolicy
+
+	// Overflow decides what happens when an item is added to a full
+	// sitemap. Writer and Splitter always reject the item, as they can't
+	// take back what they have written.
+	Overflow OverflowPolicy
+
+	// Namespaces selects the extension namespaces a Writer declares on the
+	// urlset element. Zero declares all of them.
+	Namespaces Namespace
+
+	// Dates controls how lastmod and news publication dates are written.
+	Dates DateFormat
+
+	// Stylesheet is the URL of an XSL stylesheet that browsers use to
+	// display the XML output, for example "/sitemap.xsl". Empty references
+	// none. See DefaultStylesheet.
+	Stylesheet string
+
+	// MobileMedia decides which alternates, by their media query, Mobile
+	// and BaiduMobile list as mobile pages. Nil uses IsMobileMedia.
+	MobileMedia func(media string) bool
+
+	// NewsWindow is how far back GoogleNews looks for articles. Zero uses
+	// the two days Google News accepts.
+	NewsWindow time.Duration
+
+	// Now returns the current time for the news window and for Validate.
+	// Nil uses time.Now.
+	Now func() time.Time
 }
 
 // Item represents a single URL entry in the sitemap.
 type Item struct {
-	URL        string        `xml:"loc" json:"url"`
-	LastMod    time.Time     `xml:"lastmod,omitempty" json:"lastmod,omitempty"`
-	ChangeFreq ChangeFreq    `xml:"changefreq,omitempty" json:"changefreq,omitempty"`
-	Priority   float64       `xml:"priority,omitempty" json:"priority,omitempty"`
-	Title      string        `xml:"-" json:"title,omitempty"`
-	Images     []Image       `xml:"image:image,omitempty" json:"images,omitempty"`
-	Videos     []Video       `xml:"video:video,omitempty" json:"videos,omitempty"`
-	News       *GoogleNews   `xml:"news:news,omitempty" json:"news,omitempty"`
-	Alternates []Alternate   `xml:"-" json:"alternates,omitempty"`
-	Langs      []Translation `xml:"-" json:"translations,omitempty"`
+	URL        string     `xml:"loc" json:"url"`
+	LastMod    time.Time  `xml:"lastmod,omitempty" json:"lastmod,omitempty"`
+	ChangeFreq ChangeFreq `xml:"changefreq,omitempty" json:"changefreq,omitempty"`
+	Priority   float64    `xml:"priority,omitempty" json:"priority,omitempty"`
+	// PrioritySet marks Priority as set on purpose, so that 0.0 is written
+	// instead of left out. WithPriority and ItemBuilder.Priority set it.
+	PrioritySet bool          `xml:"-" json:"-"`
+	Title       string        `xml:"-" json:"title,omitempty"`
+	Images      []Image       `xml:"image:image,omitempty" json:"images,omitempty"`
+	Videos      []Video       `xml:"video:video,omitempty" json:"videos,omitempty"`
+	News        *GoogleNews   `xml:"news:news,omitempty" json:"news,omitempty"`
+	Alternates  []Alternate   `xml:"-" json:"alternates,omitempty"`
+	Langs       []Translation `xml:"-" json:"translations,omitempty"`
+	Mobile      MobileType    `xml:"-" json:"mobile,omitempty"`
+}
+
+// MobileType marks a page for the mobile sitemaps, using the types Baidu
+// defines. The empty value leaves the page out unless it has a mobile
+// alternate.
+type MobileType string
+
+const (
+	// MobileOnly marks a page made for mobile devices.
+	MobileOnly MobileType = "mobile"
+	// MobileResponsive marks a page that serves desktop and mobile alike.
+	MobileResponsive MobileType = "pc,mobile"
+	// MobileAdaptive marks a page that adapts its code to the device.
+	MobileAdaptive MobileType = "htmladapt"
+)
+
+// hasPriority reports whether the priority should be written.
+func (i Item) hasPriority() bool {
+	return i.PrioritySet || i.Priority != 0
+}
+
+// MarshalJSON includes a priority of 0 when PrioritySet is true.
+func (i Item) MarshalJSON() ([]byte, error) {
+	type plain Item
+	out := struct {
+		plain
+		Priority *float64 `json:"priority,omitempty"`
+	}{plain: plain(i)}
+	if i.hasPriority() {
+		out.Priority = &i.Priority
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON sets PrioritySet when the priority is present and 0.
+func (i *Item) UnmarshalJSON(data []byte) error {
+	type plain Item
+	in := struct {
+		*plain
+		Priority *float64 `json:"priority"`
+	}{plain: (*plain)(i)}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+	if in.Priority != nil {
+		i.Priority, i.PrioritySet = *in.Priority, *in.Priority == 0
+	}
+	return nil
 }
 
 // Image represents an image reference in a sitemap entry.
@@ -59,14 +175,27 @@ type Image struct {
 	Caption string `xml:"image:caption,omitempty" json:"caption,omitempty"`
 }
 
-// Video represents a video reference in a sitemap entry.
+// Video represents a video reference in a sitemap entry. Zero values leave
+// the optional elements out.
 type Video struct {
-	ThumbnailURL string `xml:"video:thumbnail_loc" json:"thumbnail_url"`
-	Title        string `xml:"video:title" json:"title"`
-	Description  string `xml:"video:description" json:"description"`
-	ContentURL   string `xml:"video:content_loc,omitempty" json:"content_url,omitempty"`
-	PlayerURL    string `xml:"video:player_loc,omitempty" json:"player_url,omitempty"`
-	Duration     int    `xml:"video:duration,omitempty" json:"duration,omitempty"`
+	ThumbnailURL         string            `xml:"video:thumbnail_loc" json:"thumbnail_url"`
+	Title                string            `xml:"video:title" json:"title"`
+	Description          string            `xml:"video:description" json:"description"`
+	ContentURL           string            `xml:"video:content_loc,omitempty" json:"content_url,omitempty"`
+	PlayerURL            string            `xml:"video:player_loc,omitempty" json:"player_url,omitempty"`
+	Duration             int               `xml:"video:duration,omitempty" json:"duration,omitempty"` // seconds
+	ExpirationDate       time.Time         `xml:"video:expiration_date,omitempty" json:"expiration_date,omitempty"`
+	Rating               float64           `xml:"video:rating,omitempty" json:"rating,omitempty"` // 0.0 to 5.0
+	ViewCount            int               `xml:"video:view_count,omitempty" json:"view_count,omitempty"`
+	PublicationDate      time.Time         `xml:"video:publication_date,omitempty" json:"publication_date,omitempty"`
+	FamilyFriendly       YesNo             `xml:"video:family_friendly,omitempty" json:"family_friendly,omitempty"`
+	Restriction          *VideoRestriction `xml:"video:restriction,omitempty" json:"restriction,omitempty"`
+	Platform             *VideoPlatform    `xml:"video:platform,omitempty" json:"platform,omitempty"`
+	Prices               []VideoPrice      `xml:"video:price,omitempty" json:"prices,omitempty"`
+	RequiresSubscription YesNo             `xml:"video:requires_subscription,omitempty" json:"requires_subscription,omitempty"`
+	Uploader             *VideoUploader    `xml:"video:uploader,omitempty" json:"uploader,omitempty"`
+	Live                 YesNo             `xml:"video:live,omitempty" json:"live,omitempty"`
+	Tags                 []string          `xml:"video:tag,omitempty" json:"tags,omitempty"` // at most 32
 }
 
 // GoogleNews represents Google News specific metadata.
@@ -76,6 +205,11 @@ type GoogleNews struct {
 	PublicationDate time.Time `xml:"news:publication_date" json:"publication_date"`
 	Title           string    `xml:"news:title" json:"title"`
 	Keywords        string    `xml:"news:keywords,omitempty" json:"keywords,omitempty"`
+
+	// Fields Google no longer reads, kept for older parsers.
+	Access       string `xml:"news:access,omitempty" json:"access,omitempty"`               // "Subscription" or "Registration"
+	Genres       string `xml:"news:genres,omitempty" json:"genres,omitempty"`               // comma-separated, e.g. "PressRelease, Blog"
+	StockTickers string `xml:"news:stock_tickers,omitempty" json:"stock_tickers,omitempty"` // comma-separated, at most 5
 }
 
 // Alternate represents alternate versions of a page (mobile, print, etc.).
@@ -98,7 +232,7 @@ func New() *Sitemap {
 	return &Sitemap{
 		items: make([]Item, 0),
 		opts: Options{
-			MaxURLs: 50000,
+			MaxURLs: defaultMaxURLs,
 		},
 	}
 }
@@ -106,7 +240,7 @@ func New() *Sitemap {
 // NewWithOptions creates a new sitemap with custom options.
 func NewWithOptions(opts *Options) *Sitemap {
 	if opts.MaxURLs <= 0 {
-		opts.MaxURLs = 50000
+		opts.MaxURLs = defaultMaxURLs
 	}
 
 	items := make([]Item, 0)
@@ -122,18 +256,6 @@ func NewWithOptions(opts *Options) *Sitemap {
 
 // Add adds a URL to the sitemap with the specified parameters.
 func (s *Sitemap) Add(loc string, lastMod time.Time, priority float64, changeFreq ChangeFreq, opts ...Option) error {
-	if len(s.items) >= s.opts.MaxURLs {
-		return fmt.Errorf("sitemap has reached maximum URL limit of %d", s.opts.MaxURLs)
-	}
-
-	if err := validateURL(loc); err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
-	}
-
-	if priority < 0.0 || priority > 1.0 {
-		return fmt.Errorf("priority must be between 0.0 and 1.0, got %f", priority)
-	}
-
 	item := Item{
 		URL:        loc,
 		LastMod:    lastMod,
@@ -146,25 +268,50 @@ func (s *Sitemap) Add(loc string, lastMod time.Time, priority float64, changeFre
 		opt(&item)
 	}
 
-	s.items = append(s.items, item)
-	return nil
+	return s.add(item)
 }
 
 // AddItem adds a pre-configured item to the sitemap.
 func (s *Sitemap) AddItem(item Item) error {
-	if len(s.items) >= s.opts.MaxURLs {
-		return fmt.Errorf("sitemap has reached maximum URL limit of %d", s.opts.MaxURLs)
+	return s.add(item)
+}
+
+// add prepares an item and applies the duplicate policy.
+func (s *Sitemap) add(item Item) error {
+	item, err := prepareItem(item, &s.opts)
+	if err != nil {
+		return err
 	}
 
-	if err := validateURL(item.URL); err != nil {
-		return fmt.Errorf("invalid URL: %w", err)
+	i, exists := s.byLoc[item.URL]
+	if exists && s.opts.Duplicates != AllowDuplicates {
+		switch s.opts.Duplicates {
+		case KeepLast:
+			s.items[i] = item
+			s.changed(i)
+		case MergeDuplicates:
+			mergeItem(&s.items[i], item)
+			s.changed(i)
+		}
+		return nil
 	}
 
-	if item.Priority < 0.0 || item.Priority > 1.0 {
-		return fmt.Errorf("priority must be between 0.0 and 1.0, got %f", item.Priority)
+	if len(s.items) >= s.opts.MaxURLs {
+		if s.opts.Overflow == RejectOverflow {
+			return fmt.Errorf("%w of %d", ErrLimitReached, s.opts.MaxURLs)
+		}
+		return s.evict(item)
 	}
 
 	s.items = append(s.items, item)
+	if exists {
+		s.dups++
+		return nil
+	}
+	if s.byLoc == nil {
+		s.byLoc = make(map[string]int)
+	}
+	s.byLoc[item.URL] = len(s.items) - 1
 	return nil
 }
 
@@ -183,14 +330,59 @@ func (s *Sitemap) Count() int {
 	return len(s.items)
 }
 
-// Items returns all items in the sitemap.
+// Items returns a copy of the items in the sitemap. Changing the copy
+// doesn't affect the sitemap; use Update for that.
 func (s *Sitemap) Items() []Item {
-	return s.items
+	items := make([]Item, len(s.items))
+	for i, item := range s.items {
+		items[i] = cloneItem(item)
+	}
+	return items
+}
+
+// All returns an iterator over copies of the items, in order. The sitemap
+// must not be modified during the iteration.
+func (s *Sitemap) All() iter.Seq[Item] {
+	return func(yield func(Item) bool) {
+		for _, item := range s.items {
+			if !yield(cloneItem(item)) {
+				return
+			}
+		}
+	}
 }
 
 // Clear removes all items from the sitemap.
 func (s *Sitemap) Clear() {
-	s.items = s.items[:0]
+	if s.opts.PreAllocate {
+		s.items = make([]Item, 0, s.opts.MaxURLs)
+	} else {
+		s.items = make([]Item, 0)
+	}
+	clear(s.byLoc)
+	s.dups = 0
+	s.ranks = nil
+}
+
+// clone returns a copy of the sitemap that shares no state with it. The
+// items' slices are shared, which is safe because the sitemap never writes
+// to them in place.
+func (s *Sitemap) clone() *Sitemap {
+	return &Sitemap{
+		items: slices.Clone(s.items),
+		opts:  s.opts,
+		byLoc: maps.Clone(s.byLoc),
+		dups:  s.dups,
+	}
+}
+
+// replace takes over the contents of t, a clone of s that was changed.
+func (s *Sitemap) replace(t *Sitemap) {
+	*s = *t
+	if s.ranks != nil {
+		// The heap was built for t; point it at s.
+		s.ranks.s = s
+	}
 }
 
 // WithTitle sets the title for a sitemap item.
@@ -235,23 +427,208 @@ func WithTranslations(translations []Translation) Option {
 	}
 }
 
+// WithMobile marks a sitemap item for the mobile sitemaps.
+func WithMobile(t MobileType) Option {
+	return func(item *Item) {
+		item.Mobile = t
+	}
+}
+
+// WithPriority sets the priority of a sitemap item, overriding the
+// priority passed to Add. Unlike a priority of 0 passed to Add, which
+// leaves priority out, WithPriority(0) writes <priority>0.0</priority>.
+func WithPriority(priority float64) Option {
+	return func(item *Item) {
+		item.Priority = priority
+		item.PrioritySet = true
+	}
+}
+
+// WithImage appends an image to a sitemap item.
+func WithImage(image Image) Option {
+	return func(item *Item) {
+		item.Images = append(item.Images, image)
+	}
+}
+
+// WithVideo appends a video to a sitemap item.
+func WithVideo(video Video) Option {
+	return func(item *Item) {
+		item.Videos = append(item.Videos, video)
+	}
+}
+
+// WithAlternate appends an alternate version to a sitemap item.
+func WithAlternate(alternate Alternate) Option {
+	return func(item *Item) {
+		item.Alternates = append(item.Alternates, alternate)
+	}
+}
+
+// WithTranslation appends a translation to a sitemap item.
+func WithTranslation(translation Translation) Option {
+	return func(item *Item) {
+		item.Langs = append(item.Langs, translation)
+	}
+}
+
+// prepareItem resolves, encodes, validates and normalizes an item according
+// to opts.
+func prepareItem(item Item, opts *Options) (Item, error) {
+	if opts.BaseURL != "" {
+		var err error
+		if item, err = resolveItem(item, opts.BaseURL); err != nil {
+			return Item{}, err
+		}
+	}
+
+	item, err := mapItemURLs(item, encodeURL)
+	if err != nil {
+		return Item{}, err
+	}
+
+	if err := validateItem(item); err != nil {
+		return Item{}, err
+	}
+
+	if opts.Normalize != nil {
+		loc, err := normalizeURL(item.URL, opts.Normalize)
+		if err != nil {
+			return Item{}, err
+		}
+		item.URL = loc
+
+		// Translations point at other locations, so they are normalized
+		// the same way to keep hreflang clusters consistent.
+		item.Langs = slices.Clone(item.Langs)
+		for i := range item.Langs {
+			if item.Langs[i].URL == "" {
+				continue
+			}
+			if item.Langs[i].URL, err = normalizeURL(item.Langs[i].URL, opts.Normalize); err != nil {
+				return Item{}, err
+			}
+		}
+	}
+
+	return item, nil
+}
+
+// resolveItem resolves every relative URL in the item against baseURL.
+func resolveItem(item Item, baseURL string) (Item, error) {
+	if err := validateURL(baseURL); err != nil {
+		return Item{}, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return Item{}, fmt.Errorf("invalid base URL: %w", err)
+	}
+
+	return mapItemURLs(item, func(ref string) (string, error) {
+		u, err := url.Parse(ref)
+		if err != nil {
+			return "", &URLError{URL: ref, Err: fmt.Errorf("%w: %w", ErrMalformedURL, err)}
+		}
+		return base.ResolveReference(u).String(), nil
+	})
+}
+
+// mapItemURLs replaces every non-empty URL in the item with fn's result.
+// Slices are copied so the caller's item is left untouched.
+func mapItemURLs(item Item, fn func(string) (string, error)) (Item, error) {
+	apply := func(ref *string) error {
+		if *ref == "" {
+			return nil
+		}
+		mapped, err := fn(*ref)
+		if err != nil {
+			return err
+		}
+		*ref = mapped
+		return nil
+	}
+
+	if err := apply(&item.URL); err != nil {
+		return Item{}, err
+	}
+
+	item.Images = slices.Clone(item.Images)
+	for i := range item.Images {
+		if err := apply(&item.Images[i].URL); err != nil {
+			return Item{}, err
+		}
+	}
+
+	item.Videos = slices.Clone(item.Videos)
+	for i := range item.Videos {
+		v := &item.Videos[i]
+		refs := []*string{&v.ThumbnailURL, &v.ContentURL, &v.PlayerURL}
+		if v.Uploader != nil {
+			uploader := *v.Uploader
+			v.Uploader = &uploader
+			refs = append(refs, &uploader.Info)
+		}
+		for _, ref := range refs {
+			if err := apply(ref); err != nil {
+				return Item{}, err
+			}
+		}
+	}
+
+	item.Alternates = slices.Clone(item.Alternates)
+	for i := range item.Alternates {
+		if err := apply(&item.Alternates[i].URL); err != nil {
+			return Item{}, err
+		}
+	}
+
+	item.Langs = slices.Clone(item.Langs)
+	for i := range item.Langs {
+		if err := apply(&item.Langs[i].URL); err != nil {
+			return Item{}, err
+		}
+	}
+
+	return item, nil
+}
+
+// validateItem validates the URL and priority of an item.
+func validateItem(item Item) error {
+	if err := validateURL(item.URL); err != nil {
+		return err
+	}
+
+	if item.Priority < 0.0 || item.Priority > 1.0 {
+		return fmt.Errorf("%w, got %f", ErrPriorityRange, item.Priority)
+	}
+
+	for _, t := range item.Langs {
+		if err := validateLanguage(t.Language); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // validateURL validates that the URL is well-formed and absolute.
 func validateURL(rawURL string) error {
 	if rawURL == "" {
-		return fmt.Errorf("URL cannot be empty")
+		return &URLError{URL: rawURL, Err: ErrEmptyURL}
 	}
 
 	u, err := url.Parse(rawURL)
 	if err != nil {
-		return fmt.Errorf("invalid URL format: %w", err)
+		return &URLError{URL: rawURL, Err: fmt.Errorf("%w: %w", ErrMalformedURL, err)}
 	}
 
 	if !u.IsAbs() {
-		return fmt.Errorf("URL must be absolute")
+		return &URLError{URL: rawURL, Err: ErrRelativeURL}
 	}
 
 	if u.Scheme != "http" && u.Scheme != "https" {
-		return fmt.Errorf("URL scheme must be http or https")
+		return &URLError{URL: rawURL, Err: ErrURLScheme}
 	}
 
 	return nil
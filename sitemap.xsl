@@ -0,0 +1,155 @@
+<?xml version="1.0" encoding="UTF-8"?>
+<xsl:stylesheet version="1.0"
+    xmlns:xsl="http://www.w3.org/1999/XSL/Transform"
+    xmlns:s="http://www.sitemaps.org/schemas/sitemap/0.9"
+    xmlns:image="http://www.google.com/schemas/sitemap-image/1.1"
+    xmlns:video="http://www.google.com/schemas/sitemap-video/1.1"
+    xmlns:news="http://www.google.com/schemas/sitemap-news/0.9"
+    xmlns:xhtml="http://www.w3.org/1999/xhtml"
+    exclude-result-prefixes="s image video news xhtml">
+
+  <xsl:output method="html" encoding="UTF-8" indent="yes" doctype-system="about:legacy-compat"/>
+
+  <xsl:template match="/">
+    <html lang="en">
+      <head>
+        <meta charset="UTF-8"/>
+        <meta name="viewport" content="width=device-width, initial-scale=1.0"/>
+        <title>Sitemap</title>
+        <style>
+          body { font-family: Arial, sans-serif; margin: 20px; color: #333; }
+          h1 { color: #333; }
+          .stats { background: #e9ecef; padding: 10px; border-radius: 5px; margin-bottom: 20px; }
+          table { border-collapse: collapse; width: 100%; }
+          th, td { text-align: left; vertical-align: top; padding: 8px; border-bottom: 1px solid #ddd; }
+          th { background: #f5f5f5; }
+          tr:hover td { background: #f9f9f9; }
+          a { color: #0066cc; text-decoration: none; }
+          a:hover { text-decoration: underline; }
+          ul { margin: 0; padding-left: 18px; }
+          .meta { color: #666; font-size: 0.9em; }
+        </style>
+      </head>
+      <body>
+        <xsl:apply-templates select="s:urlset | s:sitemapindex"/>
+      </body>
+    </html>
+  </xsl:template>
+
+  <xsl:template match="s:sitemapindex">
+    <h1>Sitemap Index</h1>
+    <div class="stats">
+      <strong>Total sitemaps:</strong>
+      <xsl:text> </xsl:text>
+      <xsl:value-of select="count(s:sitemap)"/>
+    </div>
+    <table>
+      <tr>
+        <th>Sitemap</th>
+        <th>Last Modified</th>
+      </tr>
+      <xsl:for-each select="s:sitemap">
+        <tr>
+          <td><a href="{s:loc}"><xsl:value-of select="s:loc"/></a></td>
+          <td><xsl:value-of select="s:lastmod"/></td>
+        </tr>
+      </xsl:for-each>
+    </table>
+  </xsl:template>
+
+  <xsl:template match="s:urlset">
+    <h1>Sitemap</h1>
+    <div class="stats">
+      <strong>Total URLs:</strong>
+      <xsl:text> </xsl:text>
+      <xsl:value-of select="count(s:url)"/>
+    </div>
+    <table>
+      <tr>
+        <th>URL</th>
+        <th>Priority</th>
+        <th>Change Frequency</th>
+        <th>Last Modified</th>
+        <th>Images</th>
+        <th>Videos</th>
+        <th>Alternates</th>
+      </tr>
+      <xsl:for-each select="s:url">
+        <tr>
+          <td>
+            <a href="{s:loc}"><xsl:value-of select="s:loc"/></a>
+            <xsl:if test="news:news">
+              <div class="meta">
+                <xsl:text>News: </xsl:text>
+                <xsl:value-of select="news:news/news:title"/>
+              </div>
+            </xsl:if>
+          </td>
+          <td><xsl:value-of select="s:priority"/></td>
+          <td><xsl:value-of select="s:changefreq"/></td>
+          <td><xsl:value-of select="s:lastmod"/></td>
+          <td>
+            <xsl:if test="image:image">
+              <ul>
+                <xsl:for-each select="image:image">
+                  <li>
+                    <a href="{image:loc}">
+                      <xsl:choose>
+                        <xsl:when test="image:title"><xsl:value-of select="image:title"/></xsl:when>
+                        <xsl:otherwise><xsl:value-of select="image:loc"/></xsl:otherwise>
+                      </xsl:choose>
+                    </a>
+                  </li>
+                </xsl:for-each>
+              </ul>
+            </xsl:if>
+          </td>
+          <td>
+            <xsl:if test="video:video">
+              <ul>
+                <xsl:for-each select="video:video">
+                  <li>
+                    <xsl:choose>
+                      <xsl:when test="video:content_loc">
+                        <a href="{video:content_loc}"><xsl:value-of select="video:title"/></a>
+                      </xsl:when>
+                      <xsl:otherwise>
+                        <a href="{video:player_loc}"><xsl:value-of select="video:title"/></a>
+                      </xsl:otherwise>
+                    </xsl:choose>
+                    <xsl:if test="video:duration">
+                      <span class="meta">
+                        <xsl:text> (</xsl:text>
+                        <xsl:value-of select="video:duration"/>
+                        <xsl:text>s)</xsl:text>
+                      </span>
+                    </xsl:if>
+                  </li>
+                </xsl:for-each>
+              </ul>
+            </xsl:if>
+          </td>
+          <td>
+            <xsl:if test="xhtml:link">
+              <ul>
+                <xsl:for-each select="xhtml:link">
+                  <li>
+                    <span class="meta">
+                      <xsl:choose>
+                        <xsl:when test="@hreflang"><xsl:value-of select="@hreflang"/></xsl:when>
+                        <xsl:otherwise><xsl:value-of select="@media"/></xsl:otherwise>
+                      </xsl:choose>
+                    </span>
+                    <xsl:text> </xsl:text>
+                    <a href="{@href}"><xsl:value-of select="@href"/></a>
+                  </li>
+                </xsl:for-each>
+              </ul>
+            </xsl:if>
+          </td>
+        </tr>
+      </xsl:for-each>
+    </table>
+  </xsl:template>
+
+</xsl:stylesheet>
@@ -1,6 +1,7 @@
 package sitemap
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -406,3 +407,107 @@ func TestChangeFreqConstants(t *testing.T) {
 		}
 	}
 }
+
+func TestBaseURLResolution(t *testing.T) {
+	sm := NewWithOptions(&Options{BaseURL: "https://example.com/shop/"})
+	now := time.Now()
+
+	images := []Image{{URL: "img/42.jpg"}, {URL: "https://cdn.example.com/42.jpg"}}
+	err := sm.Add(
+		"products/42",
+		now,
+		0.8,
+		Weekly,
+		WithImages(images),
+		WithVideos([]Video{{ThumbnailURL: "/thumbs/42.jpg", ContentURL: "//cdn.example.com/42.mp4"}}),
+		WithAlternates([]Alternate{{Media: "print", URL: "products/42?print=1"}}),
+		WithTranslations([]Translation{{Language: "de", URL: "/de/shop/products/42"}}),
+	)
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	item := sm.Items()[0]
+	checks := map[string]string{
+		"loc":         item.URL,
+		"image":       item.Images[0].URL,
+		"abs image":   item.Images[1].URL,
+		"thumbnail":   item.Videos[0].ThumbnailURL,
+		"content":     item.Videos[0].ContentURL,
+		"player":      item.Videos[0].PlayerURL,
+		"alternate":   item.Alternates[0].URL,
+		"translation": item.Langs[0].URL,
+	}
+	expected := map[string]string{
+		"loc":         "https://example.com/shop/products/42",
+		"image":       "https://example.com/shop/img/42.jpg",
+		"abs image":   "https://cdn.example.com/42.jpg",
+		"thumbnail":   "https://example.com/thumbs/42.jpg",
+		"content":     "https://cdn.example.com/42.mp4",
+		"player":      "",
+		"alternate":   "https://example.com/shop/products/42?print=1",
+		"translation": "https://example.com/de/shop/products/42",
+	}
+	for field, got := range checks {
+		if got != expected[field] {
+			t.Errorf("%s: expected %q, got %q", field, expected[field], got)
+		}
+	}
+
+	if images[0].URL != "img/42.jpg" {
+		t.Error("Resolving should not modify the caller's slices")
+	}
+}
+
+func TestBaseURLInvalid(t *testing.T) {
+	sm := NewWithOptions(&Options{BaseURL: "/not/absolute"})
+	if err := sm.Add("/page", time.Now(), 0.5, Daily); err == nil {
+		t.Error("Add() should fail when BaseURL is not absolute")
+	}
+
+	sm = New()
+	if err := sm.Add("/page", time.Now(), 0.5, Daily); err == nil {
+		t.Error("Add() should fail for relative URLs without BaseURL")
+	}
+}
+
+func TestExplicitZeroPriority(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/unset", time.Now(), 0, Daily)
+	sm.Add("https://example.com/archive", time.Now(), 0.5, Daily, WithPriority(0))
+	item, _ := NewItem("https://example.com/built").Priority(0).Build()
+	sm.AddItem(item)
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "<priority>0.0</priority>"); n != 2 {
+		t.Errorf("Expected 2 explicit zero priorities, got %d in\n%s", n, data)
+	}
+
+	parsed, err := Parse(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := parsed.Items()
+	if items[0].PrioritySet || !items[1].PrioritySet {
+		t.Errorf("Parse() should keep explicit zero priorities, got %+v", items)
+	}
+
+	js, err := sm.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(js), `"priority": 0`); n != 2 {
+		t.Errorf("Expected 2 explicit zero priorities in JSON, got %d in\n%s", n, js)
+	}
+
+	var decoded Item
+	if err := json.Unmarshal([]byte(`{"url": "https://example.com/", "priority": 0}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.PrioritySet {
+		t.Error("UnmarshalJSON() should mark an explicit zero priority")
+	}
+}
@@ -0,0 +1,245 @@
+package sitemap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CreateFunc opens the destination for a sitemap file with the given name.
+type CreateFunc func(name string) (io.WriteCloser, error)
+
+// SplitOptions contains configuration options for a Splitter.
+type SplitOptions struct {
+	// Options apply to every part. MaxURLs and MaxBytes decide when the
+	// Splitter rolls over to a new part.
+	Options Options
+
+	// URL is the location the parts are published under, for example
+	// "https://example.com/sitemaps/". It is used for the index entries and
+	// is required: it must be an absolute http or https URL, or AddItem
+	// fails before any part is created.
+	URL string
+
+	// Prefix names the parts <Prefix>-1.xml, <Prefix>-2.xml and so on.
+	// Defaults to "sitemap".
+	Prefix string
+
+	// Compress gzips every part and names it with a .xml.gz extension.
+	// Options.MaxBytes still limits the uncompressed size.
+	Compress bool
+}
+
+// Splitter streams items into numbered sitemap files, starting a new file
+// whenever the URL count or byte limit would be exceeded, and builds an
+// Index that references every file.
+type Splitter struct {
+	create  CreateFunc
+	opts    SplitOptions
+	writer  *Writer
+	file    io.WriteCloser
+	name    string
+	lastMod time.Time
+	parts   int
+	count   int
+	index   *Index
+	closed  bool
+}
+
+// NewSplitter creates a Splitter that opens each part through create.
+func NewSplitter(create CreateFunc, opts *SplitOptions) *Splitter {
+	s := &Splitter{
+		create: create,
+	}
+	if opts != nil {
+		s.opts = *opts
+	}
+	s.index = NewIndexWithOptions(&IndexOptions{
+		Dates:      s.opts.Options.Dates,
+		Stylesheet: s.opts.Options.Stylesheet,
+	})
+	if s.opts.Prefix == "" {
+		s.opts.Prefix = "sitemap"
+	}
+	return s
+}
+
+// Add adds a URL with the specified parameters.
+func (s *Splitter) Add(loc string, lastMod time.Time, priority float64, changeFreq ChangeFreq, opts ...Option) error {
+	item := Item{
+		URL:        loc,
+		LastMod:    lastMod,
+		Priority:   priority,
+		ChangeFreq: changeFreq,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(&item)
+	}
+
+	return s.AddItem(item)
+}
+
+// AddItem adds a pre-configured item, rolling over to a new part if the
+// current one is full.
+func (s *Splitter) AddItem(item Item) error {
+	if s.closed {
+		return ErrClosed
+	}
+
+	// Check the index location before the first part is written.
+	if s.parts == 0 {
+		if err := validateURL(s.opts.URL); err != nil {
+			return fmt.Errorf("splitter URL: %w", err)
+		}
+	}
+
+	// Reject invalid items before a part is opened for them.
+	prepared, err := prepareItem(item, &s.opts.Options)
+	if err != nil {
+		return err
+	}
+	if ns := s.opts.Options.Namespaces; ns != 0 {
+		if err := checkNamespaces(prepared, ns); err != nil {
+			return err
+		}
+	}
+
+	// Without an open part the item needs a new one, as if the last was full.
+	full := s.writer == nil
+	if !full {
+		err = s.writer.AddItem(item)
+		full = errors.Is(err, ErrLimitReached) || errors.Is(err, ErrSizeLimitReached)
+	}
+	if full {
+		// Roll over only for an item that fits in a part of its own, so no
+		// part is cut short or left empty for it.
+		if err := NewWriter(io.Discard, &s.opts.Options).AddItem(item); err != nil {
+			return err
+		}
+		if s.writer != nil {
+			if err := s.finish(); err != nil {
+				return err
+			}
+		}
+		if err := s.open(); err != nil {
+			return err
+		}
+		err = s.writer.AddItem(item)
+	}
+	if err != nil {
+		return err
+	}
+
+	if item.LastMod.After(s.lastMod) {
+		s.lastMod = item.LastMod
+	}
+	s.count++
+	return nil
+}
+
+// AddSeq writes the items produced by seq as they arrive, starting new
+// parts as needed. It stops at the first error, either from seq or from
+// AddItem, and returns it with the item's position.
+func (s *Splitter) AddSeq(seq iter.Seq2[Item, error]) error {
+	i := 0
+	for item, err := range seq {
+		if err == nil {
+			err = s.AddItem(item)
+		}
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		i++
+	}
+	return nil
+}
+
+// Count returns the total number of URLs written across all parts.
+func (s *Splitter) Count() int {
+	return s.count
+}
+
+// Parts returns the number of sitemap files opened so far.
+func (s *Splitter) Parts() int {
+	return s.parts
+}
+
+// Close finishes the current part. Calling Close more than once is a no-op.
+func (s *Splitter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.writer == nil {
+		return nil
+	}
+	return s.finish()
+}
+
+// Index returns the sitemap index referencing every finished part, with
+// each entry's LastMod set to the newest LastMod in that part.
+func (s *Splitter) Index() *Index {
+	return s.index
+}
+
+// open starts the next numbered part.
+func (s *Splitter) open() error {
+	name := PartName(s.opts.Prefix, s.parts+1, s.opts.Compress)
+
+	file, err := s.create(name)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", name, err)
+	}
+	if s.opts.Compress {
+		file = newGzipWriteCloser(file)
+	}
+
+	s.parts++
+	s.name = name
+	s.file = file
+	s.writer = NewWriter(file, &s.opts.Options)
+	s.lastMod = time.Time{}
+	return nil
+}
+
+// finish closes the current part and records it in the index.
+func (s *Splitter) finish() error {
+	count := s.writer.Count()
+	err := s.writer.Close()
+	if cerr := s.file.Close(); err == nil {
+		err = cerr
+	}
+	s.writer = nil
+	s.file = nil
+	if err != nil {
+		return fmt.Errorf("closing %s: %w", s.name, err)
+	}
+
+	// A sitemap must hold at least one URL, so an empty part is not
+	// indexed.
+	if count == 0 {
+		return nil
+	}
+
+	loc := strings.TrimSuffix(s.opts.URL, "/") + "/" + s.name
+	if err := s.index.Add(loc, s.lastMod); err != nil {
+		return fmt.Errorf("indexing %s: %w", s.name, err)
+	}
+
+	return nil
+}
+
+// FileCreator returns a CreateFunc that creates the parts as files in dir.
+func FileCreator(dir string) CreateFunc {
+	return func(name string) (io.WriteCloser, error) {
+		return os.Create(filepath.Join(dir, name))
+	}
+}
@@ -0,0 +1,323 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bufferCloser struct {
+	*bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+// memoryFiles collects the parts written by a Splitter.
+type memoryFiles struct {
+	names []string
+	files map[string]*bufferCloser
+}
+
+func (m *memoryFiles) create(name string) (io.WriteCloser, error) {
+	if m.files == nil {
+		m.files = make(map[string]*bufferCloser)
+	}
+	b := &bufferCloser{Buffer: &bytes.Buffer{}}
+	m.names = append(m.names, name)
+	m.files[name] = b
+	return b, nil
+}
+
+func TestSplitterMaxURLs(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{MaxURLs: 2},
+		URL:     "https://example.com/sitemaps/",
+	})
+
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		err := s.Add(fmt.Sprintf("https://example.com/page%d", i), base.AddDate(0, 0, i), 0.5, Daily)
+		if err != nil {
+			t.Fatalf("Add() failed for item %d: %v", i, err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if s.Count() != 5 {
+		t.Errorf("Expected 5 items, got %d", s.Count())
+	}
+
+	if s.Parts() != 3 {
+		t.Fatalf("Expected 3 parts, got %d", s.Parts())
+	}
+
+	expectedNames := []string{"sitemap-1.xml", "sitemap-2.xml", "sitemap-3.xml"}
+	for i, name := range expectedNames {
+		if files.names[i] != name {
+			t.Errorf("Part %d: expected name %s, got %s", i, name, files.names[i])
+		}
+
+		f := files.files[name]
+		if !f.closed {
+			t.Errorf("Part %s should be closed", name)
+		}
+
+		var urlset struct {
+			URLs []struct {
+				Loc string `xml:"loc"`
+			} `xml:"url"`
+		}
+		if err := xml.Unmarshal(f.Bytes(), &urlset); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", name, err)
+		}
+
+		expected := 2
+		if i == 2 {
+			expected = 1
+		}
+		if len(urlset.URLs) != expected {
+			t.Errorf("Part %s: expected %d URLs, got %d", name, expected, len(urlset.URLs))
+		}
+	}
+
+	idx := s.Index()
+	if idx.Count() != 3 {
+		t.Fatalf("Expected 3 index entries, got %d", idx.Count())
+	}
+
+	xmlData, err := idx.XML()
+	if err != nil {
+		t.Fatalf("Index XML() failed: %v", err)
+	}
+
+	xmlStr := string(xmlData)
+	if !strings.Contains(xmlStr, "<loc>https://example.com/sitemaps/sitemap-2.xml</loc>") {
+		t.Error("Index should reference the second part")
+	}
+
+	// The second part holds items 2 and 3, so its lastmod is item 3's.
+	if !strings.Contains(xmlStr, base.AddDate(0, 0, 3).Format(time.RFC3339)) {
+		t.Error("Index should use the newest LastMod of each part")
+	}
+}
+
+func TestSplitterMaxBytes(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{MaxBytes: 600},
+		URL:     "https://example.com",
+		Prefix:  "products",
+	})
+
+	for i := 0; i < 10; i++ {
+		if err := s.Add(fmt.Sprintf("https://example.com/product/%d", i), time.Time{}, 0.5, Weekly); err != nil {
+			t.Fatalf("Add() failed for item %d: %v", i, err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if s.Parts() < 2 {
+		t.Fatalf("Expected the byte limit to force several parts, got %d", s.Parts())
+	}
+
+	total := 0
+	for _, name := range files.names {
+		if !strings.HasPrefix(name, "products-") {
+			t.Errorf("Unexpected part name %s", name)
+		}
+
+		data := files.files[name].Bytes()
+		if len(data) > 600 {
+			t.Errorf("Part %s is %d bytes, exceeding the 600 byte limit", name, len(data))
+		}
+		total += strings.Count(string(data), "<url>")
+	}
+
+	if total != 10 {
+		t.Errorf("Expected 10 URLs across all parts, got %d", total)
+	}
+
+	xmlData, _ := s.Index().XML()
+	if !strings.Contains(string(xmlData), "https://example.com/products-1.xml") {
+		t.Error("Index should join URL and part name with a slash")
+	}
+}
+
+func TestSplitterItemTooLarge(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{MaxBytes: 100},
+		URL:     "https://example.com/",
+	})
+
+	err := s.Add("https://example.com/"+strings.Repeat("a", 200), time.Time{}, 0.5, Daily)
+	if err == nil {
+		t.Error("Add() should fail for an item larger than MaxBytes")
+	}
+}
+
+func TestSplitterOversizedItem(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{MaxBytes: 800},
+		URL:     "https://example.com/",
+	})
+	huge := "https://example.com/" + strings.Repeat("a", 1000)
+
+	// A first item that can never fit opens no part.
+	if err := s.Add(huge, time.Time{}, 0.5, Daily); !errors.Is(err, ErrSizeLimitReached) {
+		t.Errorf("Expected ErrSizeLimitReached, got %v", err)
+	}
+	if len(files.names) != 0 {
+		t.Errorf("No part should be created for an oversized item, got %v", files.names)
+	}
+
+	// One in the middle leaves the current part open for later items.
+	s.Add("https://example.com/1", time.Time{}, 0.5, Daily)
+	if err := s.Add(huge, time.Time{}, 0.5, Daily); !errors.Is(err, ErrSizeLimitReached) {
+		t.Errorf("Expected ErrSizeLimitReached, got %v", err)
+	}
+	s.Add("https://example.com/2", time.Time{}, 0.5, Daily)
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if s.Parts() != 1 || s.Index().Count() != 1 {
+		t.Fatalf("Expected a single indexed part, got %d parts %v", s.Parts(), files.names)
+	}
+	part := files.files["sitemap-1.xml"].String()
+	if !strings.Contains(part, "https://example.com/1") || !strings.Contains(part, "https://example.com/2") {
+		t.Errorf("Both small items should share the part:\n%s", part)
+	}
+}
+
+func TestSplitterEmptyPartNotIndexed(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{URL: "https://example.com/"})
+
+	// Force an open part without items, as a failed first write leaves.
+	if err := s.open(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if s.Index().Count() != 0 {
+		t.Errorf("An empty part should not be indexed, got %d entries", s.Index().Count())
+	}
+}
+
+func TestSplitterInvalidItem(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{URL: "https://example.com/"})
+
+	if err := s.AddItem(Item{URL: "/relative"}); err == nil {
+		t.Error("AddItem() should fail for relative URL")
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if len(files.names) != 0 {
+		t.Errorf("No part should be created for invalid items, got %v", files.names)
+	}
+
+	if s.Index().Count() != 0 {
+		t.Errorf("Expected empty index, got %d entries", s.Index().Count())
+	}
+
+	if err := s.AddItem(Item{URL: "https://example.com/"}); err == nil {
+		t.Error("AddItem() should fail after Close()")
+	}
+}
+
+func TestSplitterRequiresURL(t *testing.T) {
+	for _, loc := range []string{"", "/sitemaps/"} {
+		files := &memoryFiles{}
+		s := NewSplitter(files.create, &SplitOptions{URL: loc})
+
+		err := s.Add("https://example.com/", time.Time{}, 0.5, Daily)
+		var urlErr *URLError
+		if !errors.As(err, &urlErr) {
+			t.Errorf("Add() with URL %q should fail with a *URLError, got %v", loc, err)
+		}
+		if len(files.names) != 0 {
+			t.Errorf("No part should be created without a valid URL, got %v", files.names)
+		}
+	}
+}
+
+func TestFileCreator(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSplitter(FileCreator(dir), &SplitOptions{URL: "https://example.com/"})
+
+	if err := s.Add("https://example.com/", time.Now(), 1.0, Daily); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sitemap-1.xml"))
+	if err != nil {
+		t.Fatalf("Failed to read part: %v", err)
+	}
+
+	if !strings.Contains(string(data), "<loc>https://example.com/</loc>") {
+		t.Error("Part file should contain the URL")
+	}
+}
+
+func TestSplitterBaseURL(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{BaseURL: "https://example.com"},
+		URL:     "https://example.com/",
+	})
+
+	if err := s.Add("/products/42", time.Time{}, 0.5, Daily); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+	s.Close()
+
+	if !strings.Contains(files.files["sitemap-1.xml"].String(), "<loc>https://example.com/products/42</loc>") {
+		t.Error("Part should contain the resolved URL")
+	}
+}
+
+func TestSplitterAddSeq(t *testing.T) {
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{
+		Options: Options{MaxURLs: 2},
+		URL:     "https://example.com/",
+	})
+
+	if err := s.AddSeq(rows(5, nil)); err != nil {
+		t.Fatalf("AddSeq() failed: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if s.Count() != 5 || s.Parts() != 3 {
+		t.Errorf("Expected 5 items in 3 parts, got %d in %d", s.Count(), s.Parts())
+	}
+}
@@ -0,0 +1,36 @@
+package sitemap
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+)
+
+// defaultStylesheet renders sitemaps and sitemap indexes as HTML tables,
+// including images, videos and alternate links.
+//
+//go:embed sitemap.xsl
+var defaultStylesheet []byte
+
+// DefaultStylesheet returns the bundled XSL stylesheet. Serve it, for
+// example at /sitemap.xsl, and point Options.Stylesheet or
+// IndexOptions.Stylesheet at it so browsers show the sitemap as a table.
+func DefaultStylesheet() []byte {
+	return bytes.Clone(defaultStylesheet)
+}
+
+// writeHeader writes the XML declaration, followed by an xml-stylesheet
+// processing instruction when stylesheet is set.
+func writeHeader(buf *bytes.Buffer, stylesheet string) {
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
+	buf.WriteByte('\n')
+
+	if stylesheet == "" {
+		return
+	}
+	buf.WriteString(`<?xml-stylesheet type="text/xsl" href="`)
+	// Escaping '>' also keeps "?>" from ending the instruction early.
+	xml.EscapeText(buf, []byte(sanitizeText(stylesheet)))
+	buf.WriteString(`"?>`)
+	buf.WriteByte('\n')
+}
@@ -0,0 +1,92 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+const stylesheetPI = `<?xml-stylesheet type="text/xsl" href="/sitemap.xsl"?>`
+
+func TestStylesheetInstruction(t *testing.T) {
+	sm := NewWithOptions(&Options{Stylesheet: "/sitemap.xsl"})
+	sm.Add("https://example.com/", time.Now(), 0.5, Daily)
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+	lines := strings.SplitN(string(data), "\n", 3)
+	if lines[0] != `<?xml version="1.0" encoding="UTF-8"?>` || lines[1] != stylesheetPI {
+		t.Errorf("Expected the declaration followed by the stylesheet, got:\n%s", data)
+	}
+	if err := xml.Unmarshal(data, new(parseURLSet)); err != nil {
+		t.Errorf("Output should stay well-formed: %v", err)
+	}
+
+	idx := NewIndexWithOptions(&IndexOptions{Stylesheet: "/sitemap.xsl"})
+	idx.Add("https://example.com/sitemap-1.xml", time.Now())
+	data, err = idx.XML()
+	if err != nil {
+		t.Fatalf("Index.XML() failed: %v", err)
+	}
+	if !strings.Contains(string(data), stylesheetPI) {
+		t.Errorf("Index should reference the stylesheet:\n%s", data)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Stylesheet: "/sitemap.xsl"})
+	w.Add("https://example.com/", time.Now(), 0.5, Daily)
+	w.Close()
+	if !strings.Contains(buf.String(), stylesheetPI) {
+		t.Errorf("Writer should reference the stylesheet:\n%s", buf.String())
+	}
+	if int64(buf.Len()) != w.Written() {
+		t.Errorf("Written() = %d, expected %d", w.Written(), buf.Len())
+	}
+
+	data, _ = New().XML()
+	if strings.Contains(string(data), "xml-stylesheet") {
+		t.Error("No stylesheet should be referenced by default")
+	}
+}
+
+func TestStylesheetEscaping(t *testing.T) {
+	sm := NewWithOptions(&Options{Stylesheet: `/style.xsl?a=1&b="2"?>`})
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+	expected := `<?xml-stylesheet type="text/xsl" href="/style.xsl?a=1&amp;b=&quot;2&quot;?&gt;"?>`
+	if !strings.Contains(string(data), expected) {
+		t.Errorf("Expected %s in:\n%s", expected, data)
+	}
+}
+
+func TestDefaultStylesheet(t *testing.T) {
+	xsl := DefaultStylesheet()
+
+	var doc struct {
+		XMLName xml.Name
+	}
+	if err := xml.Unmarshal(xsl, &doc); err != nil {
+		t.Fatalf("DefaultStylesheet() should be well-formed: %v", err)
+	}
+	if doc.XMLName.Local != "stylesheet" || doc.XMLName.Space != "http://www.w3.org/1999/XSL/Transform" {
+		t.Errorf("Unexpected root element %v", doc.XMLName)
+	}
+
+	for _, ns := range []string{sitemapNamespace, imageNamespace, videoNamespace, xhtmlNamespace} {
+		if !bytes.Contains(xsl, []byte(ns)) {
+			t.Errorf("DefaultStylesheet() should declare %s", ns)
+		}
+	}
+
+	xsl[0] = 'x'
+	if DefaultStylesheet()[0] == 'x' {
+		t.Error("DefaultStylesheet() should return a copy")
+	}
+}
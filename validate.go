@@ -0,0 +1,437 @@
+package sitemap
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+)
+
+// Documented limits checked by Validate.
+const (
+	maxLocLength              = 2048
+	maxImagesPerURL           = 1000
+	maxVideoTitleLength       = 100
+	maxVideoDescriptionLength = 2048
+	maxVideoDuration          = 28800
+	maxVideoRating            = 5
+	maxVideoTags              = 32
+	maxVideoUploaderLength    = 255
+)
+
+// Severity describes how serious a validation finding is.
+type Severity int
+
+const (
+	// SeverityWarning marks output that is accepted but likely unintended.
+	SeverityWarning Severity = iota
+	// SeverityError marks output that violates the protocol or an extension.
+	SeverityError
+)
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Rule identifies the check that produced a finding.
+type Rule string
+
+const (
+	RuleInvalidURL          Rule = "invalid-url"
+	RuleLocLength           Rule = "loc-length"
+	RuleURLCount            Rule = "url-count"
+	RuleDuplicateLoc        Rule = "duplicate-loc"
+	RuleMixedHosts          Rule = "mixed-hosts"
+	RulePriorityRange       Rule = "priority-range"
+	RuleImageCount          Rule = "image-count"
+	RuleVideoTitle          Rule = "video-title"
+	RuleVideoDescription    Rule = "video-description"
+	RuleVideoThumbnail      Rule = "video-thumbnail"
+	RuleVideoLocation       Rule = "video-location"
+	RuleVideoDuration       Rule = "video-duration"
+	RuleVideoRating         Rule = "video-rating"
+	RuleVideoExpiration     Rule = "video-expiration"
+	RuleVideoFlag           Rule = "video-flag"
+	RuleVideoRestriction    Rule = "video-restriction"
+	RuleVideoPlatform       Rule = "video-platform"
+	RuleVideoPrice          Rule = "video-price"
+	RuleVideoUploader       Rule = "video-uploader"
+	RuleVideoTags           Rule = "video-tags"
+	RuleNewsLanguage        Rule = "news-language"
+	RuleNewsPublicationDate Rule = "news-publication-date"
+	RuleNewsAge             Rule = "news-age"
+	RuleNewsAccess          Rule = "news-access"
+	RuleNewsGenres          Rule = "news-genres"
+	RuleNewsStockTickers    Rule = "news-stock-tickers"
+	RuleMobileType          Rule = "mobile-type"
+	RuleHreflangLanguage    Rule = "hreflang-language"
+	RuleHreflangReciprocity Rule = "hreflang-reciprocity"
+)
+
+// Finding is a single problem reported by Validate.
+type Finding struct {
+	Severity Severity
+	Index    int    // index of the offending item, or -1 for the whole document
+	Field    string // e.g. "loc" or "videos[0].title"
+	Rule     Rule
+	Message  string
+}
+
+// String formats the finding for logs and CI output.
+func (f Finding) String() string {
+	if f.Index < 0 {
+		return fmt.Sprintf("%s: %s: %s", f.Severity, f.Rule, f.Message)
+	}
+	return fmt.Sprintf("%s: item %d: %s: %s: %s", f.Severity, f.Index, f.Field, f.Rule, f.Message)
+}
+
+// HasErrors reports whether any of the findings is an error.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// findings collects validation results.
+type findings []Finding
+
+func (fs *findings) add(sev Severity, index int, field string, rule Rule, format string, args ...any) {
+	*fs = append(*fs, Finding{
+		Severity: sev,
+		Index:    index,
+		Field:    field,
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks the whole sitemap against the protocol limits and the
+// image, video, news and hreflang extension rules. It returns nil if
+// nothing was found.
+func (s *Sitemap) Validate() []Finding {
+	var fs findings
+	now := s.now()
+
+	if len(s.items) > defaultMaxURLs {
+		fs.add(SeverityError, -1, "", RuleURLCount, "sitemap has %d URLs, the limit is %d", len(s.items), defaultMaxURLs)
+	}
+
+	hosts := make(map[string]int)
+	seen := make(map[string]int, len(s.items))
+	for i, item := range s.items {
+		validateLoc(&fs, i, "loc", item.URL, hosts)
+
+		if first, ok := seen[item.URL]; ok {
+			fs.add(SeverityWarning, i, "loc", RuleDuplicateLoc, "%s duplicates item %d", item.URL, first)
+		} else {
+			seen[item.URL] = i
+		}
+
+		if item.Priority < 0.0 || item.Priority > 1.0 {
+			fs.add(SeverityError, i, "priority", RulePriorityRange, "priority must be between 0.0 and 1.0, got %g", item.Priority)
+		}
+
+		if len(item.Images) > maxImagesPerURL {
+			fs.add(SeverityError, i, "images", RuleImageCount, "%d images, the limit is %d", len(item.Images), maxImagesPerURL)
+		}
+		for j, img := range item.Images {
+			validateRef(&fs, i, fmt.Sprintf("images[%d].loc", j), img.URL)
+		}
+
+		for j, video := range item.Videos {
+			validateVideo(&fs, i, j, video, now)
+		}
+
+		if item.News != nil {
+			validateNews(&fs, i, item.News, now, s.newsWindow())
+		}
+
+		switch item.Mobile {
+		case "", MobileOnly, MobileResponsive, MobileAdaptive:
+		default:
+			fs.add(SeverityError, i, "mobile", RuleMobileType, "unknown mobile type %q, expected %s, %s or %s", item.Mobile, MobileOnly, MobileResponsive, MobileAdaptive)
+		}
+
+		for j, t := range item.Langs {
+			if err := validateLanguage(t.Language); err != nil {
+				fs.add(SeverityError, i, fmt.Sprintf("translations[%d].language", j), RuleHreflangLanguage, "%v", err)
+			}
+		}
+	}
+
+	if len(hosts) > 1 {
+		fs.add(SeverityError, -1, "", RuleMixedHosts, "URLs span %d hosts: %s", len(hosts), hostList(hosts))
+	}
+
+	for _, p := range s.hreflangProblems() {
+		fs.add(SeverityWarning, p.index, fmt.Sprintf("translations[%d].url", p.lang), RuleHreflangReciprocity, "%s", p.message)
+	}
+
+	return fs
+}
+
+// Validate checks the sitemap index against the protocol limits. It returns
+// nil if nothing was found.
+func (idx *Index) Validate() []Finding {
+	var fs findings
+
+	if len(idx.sitemaps) > defaultMaxURLs {
+		fs.add(SeverityError, -1, "", RuleURLCount, "index has %d sitemaps, the limit is %d", len(idx.sitemaps), defaultMaxURLs)
+	}
+
+	hosts := make(map[string]int)
+	seen := make(map[string]int, len(idx.sitemaps))
+	for i, sitemap := range idx.sitemaps {
+		validateLoc(&fs, i, "loc", sitemap.URL, hosts)
+
+		if first, ok := seen[sitemap.URL]; ok {
+			fs.add(SeverityWarning, i, "loc", RuleDuplicateLoc, "%s duplicates entry %d", sitemap.URL, first)
+		} else {
+			seen[sitemap.URL] = i
+		}
+	}
+
+	if len(hosts) > 1 {
+		fs.add(SeverityError, -1, "", RuleMixedHosts, "sitemaps span %d hosts: %s", len(hosts), hostList(hosts))
+	}
+
+	return fs
+}
+
+// validateLoc checks a location and records its host.
+func validateLoc(fs *findings, index int, field, loc string, hosts map[string]int) {
+	if n := len(loc); n > maxLocLength {
+		fs.add(SeverityError, index, field, RuleLocLength, "%d characters, the limit is %d", n, maxLocLength)
+	}
+
+	if err := validateURL(loc); err != nil {
+		fs.add(SeverityError, index, field, RuleInvalidURL, "%v", err)
+		return
+	}
+
+	if u, err := url.Parse(loc); err == nil {
+		hosts[strings.ToLower(u.Host)]++
+	}
+}
+
+// validateRef checks a URL referenced from an item, such as an image.
+func validateRef(fs *findings, index int, field, ref string) {
+	if err := validateURL(ref); err != nil {
+		fs.add(SeverityError, index, field, RuleInvalidURL, "%v", err)
+	}
+}
+
+// validateVideo checks a video against the Google video extension rules.
+func validateVideo(fs *findings, index, n int, video Video, now time.Time) {
+	field := func(name string) string {
+		return fmt.Sprintf("videos[%d].%s", n, name)
+	}
+
+	if video.ThumbnailURL == "" {
+		fs.add(SeverityError, index, field("thumbnail_loc"), RuleVideoThumbnail, "thumbnail_loc is required")
+	} else {
+		validateRef(fs, index, field("thumbnail_loc"), video.ThumbnailURL)
+	}
+
+	if video.Title == "" {
+		fs.add(SeverityError, index, field("title"), RuleVideoTitle, "title is required")
+	} else if n := utf8.RuneCountInString(video.Title); n > maxVideoTitleLength {
+		fs.add(SeverityError, index, field("title"), RuleVideoTitle, "%d characters, the limit is %d", n, maxVideoTitleLength)
+	}
+
+	if video.Description == "" {
+		fs.add(SeverityError, index, field("description"), RuleVideoDescription, "description is required")
+	} else if n := utf8.RuneCountInString(video.Description); n > maxVideoDescriptionLength {
+		fs.add(SeverityError, index, field("description"), RuleVideoDescription, "%d characters, the limit is %d", n, maxVideoDescriptionLength)
+	}
+
+	if video.ContentURL == "" && video.PlayerURL == "" {
+		fs.add(SeverityError, index, field("content_loc"), RuleVideoLocation, "content_loc or player_loc is required")
+	}
+	if video.ContentURL != "" {
+		validateRef(fs, index, field("content_loc"), video.ContentURL)
+	}
+	if video.PlayerURL != "" {
+		validateRef(fs, index, field("player_loc"), video.PlayerURL)
+	}
+
+	// Zero leaves the duration out.
+	if video.Duration < 0 || video.Duration > maxVideoDuration {
+		fs.add(SeverityError, index, field("duration"), RuleVideoDuration, "duration must be between 1 and %d seconds, or 0 to leave it out, got %d", maxVideoDuration, video.Duration)
+	}
+
+	if video.Rating < 0 || video.Rating > maxVideoRating {
+		fs.add(SeverityError, index, field("rating"), RuleVideoRating, "rating must be between 0.0 and %d.0, got %g", maxVideoRating, video.Rating)
+	}
+
+	if !video.ExpirationDate.IsZero() && video.ExpirationDate.Before(now) {
+		fs.add(SeverityWarning, index, field("expiration_date"), RuleVideoExpiration, "expiration_date %s has passed", video.ExpirationDate.Format(time.RFC3339))
+	}
+
+	flags := []struct {
+		name  string
+		value YesNo
+	}{
+		{"family_friendly", video.FamilyFriendly},
+		{"requires_subscription", video.RequiresSubscription},
+		{"live", video.Live},
+	}
+	for _, f := range flags {
+		if f.value != "" && f.value != Yes && f.value != No {
+			fs.add(SeverityError, index, field(f.name), RuleVideoFlag, "%s must be yes or no, got %q", f.name, f.value)
+		}
+	}
+
+	if r := video.Restriction; r != nil {
+		validateRelationship(fs, index, field("restriction"), RuleVideoRestriction, r.Relationship)
+		for _, country := range r.Countries {
+			if !isCountryCode(country) {
+				fs.add(SeverityError, index, field("restriction"), RuleVideoRestriction, "%q is not an ISO 3166 alpha-2 country code", country)
+			}
+		}
+	}
+
+	if p := video.Platform; p != nil {
+		validateRelationship(fs, index, field("platform"), RuleVideoPlatform, p.Relationship)
+		for _, platform := range p.Platforms {
+			if platform != "web" && platform != "mobile" && platform != "tv" {
+				fs.add(SeverityError, index, field("platform"), RuleVideoPlatform, "platform must be web, mobile or tv, got %q", platform)
+			}
+		}
+	}
+
+	for j, price := range video.Prices {
+		validatePrice(fs, index, field(fmt.Sprintf("prices[%d]", j)), price)
+	}
+
+	if u := video.Uploader; u != nil {
+		if u.Name == "" {
+			fs.add(SeverityError, index, field("uploader"), RuleVideoUploader, "uploader name is required")
+		} else if n := utf8.RuneCountInString(u.Name); n > maxVideoUploaderLength {
+			fs.add(SeverityError, index, field("uploader"), RuleVideoUploader, "%d characters, the limit is %d", n, maxVideoUploaderLength)
+		}
+		if u.Info != "" {
+			validateRef(fs, index, field("uploader.info"), u.Info)
+		}
+	}
+
+	if n := len(video.Tags); n > maxVideoTags {
+		fs.add(SeverityError, index, field("tags"), RuleVideoTags, "%d tags, the limit is %d", n, maxVideoTags)
+	}
+}
+
+// validateRelationship checks the relationship attribute of a video
+// restriction or platform list.
+func validateRelationship(fs *findings, index int, field string, rule Rule, r Relationship) {
+	if r != Allow && r != Deny {
+		fs.add(SeverityError, index, field, rule, "relationship must be allow or deny, got %q", r)
+	}
+}
+
+// validatePrice checks a video price.
+func validatePrice(fs *findings, index int, field string, price VideoPrice) {
+	if price.Amount < 0 {
+		fs.add(SeverityError, index, field, RuleVideoPrice, "amount must not be negative, got %g", price.Amount)
+	}
+	if !isCurrencyCode(price.Currency) {
+		fs.add(SeverityError, index, field, RuleVideoPrice, "%q is not an ISO 4217 currency code", price.Currency)
+	}
+	if price.Type != "" && price.Type != "rent" && price.Type != "own" {
+		fs.add(SeverityError, index, field, RuleVideoPrice, "type must be rent or own, got %q", price.Type)
+	}
+	if price.Resolution != "" && price.Resolution != "HD" && price.Resolution != "SD" {
+		fs.add(SeverityError, index, field, RuleVideoPrice, "resolution must be HD or SD, got %q", price.Resolution)
+	}
+}
+
+// isCountryCode reports whether code is an uppercase ISO 3166 alpha-2 code.
+func isCountryCode(code string) bool {
+	if len(code) != 2 || code != strings.ToUpper(code) {
+		return false
+	}
+	region, err := language.ParseRegion(code)
+	return err == nil && region.IsCountry()
+}
+
+// isCurrencyCode reports whether code is an uppercase ISO 4217 code.
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 || code != strings.ToUpper(code) {
+		return false
+	}
+	_, err := currency.ParseISO(code)
+	return err == nil
+}
+
+// validateNews checks news metadata against the Google News extension rules.
+// Articles older than window are reported because GoogleNews leaves them
+// out.
+func validateNews(fs *findings, index int, news *GoogleNews, now time.Time, window time.Duration) {
+	if err := validateNewsLanguage(news.Language); err != nil {
+		fs.add(SeverityError, index, "news.language", RuleNewsLanguage, "%v", err)
+	}
+
+	switch {
+	case news.PublicationDate.IsZero():
+		fs.add(SeverityError, index, "news.publication_date", RuleNewsPublicationDate, "publication_date is required")
+	case news.PublicationDate.After(now):
+		fs.add(SeverityWarning, index, "news.publication_date", RuleNewsPublicationDate, "publication_date %s is in the future", news.PublicationDate.Format(time.RFC3339))
+	case news.PublicationDate.Before(now.Add(-window)):
+		fs.add(SeverityWarning, index, "news.publication_date", RuleNewsAge, "published more than %s ago, Google News ignores it", window)
+	}
+
+	if news.Access != "" && news.Access != "Subscription" && news.Access != "Registration" {
+		fs.add(SeverityError, index, "news.access", RuleNewsAccess, "access must be Subscription or Registration, got %q", news.Access)
+	}
+
+	for _, genre := range splitList(news.Genres) {
+		if !slices.Contains(newsGenres, genre) {
+			fs.add(SeverityError, index, "news.genres", RuleNewsGenres, "unknown genre %q, expected one of %s", genre, strings.Join(newsGenres, ", "))
+		}
+	}
+
+	if n := len(splitList(news.StockTickers)); n > maxNewsStockTickers {
+		fs.add(SeverityError, index, "news.stock_tickers", RuleNewsStockTickers, "%d stock tickers, the limit is %d", n, maxNewsStockTickers)
+	}
+}
+
+// validateNewsLanguage checks for an ISO 639 code, plus the zh-cn and
+// zh-tw forms Google News accepts for Chinese.
+func validateNewsLanguage(lang string) error {
+	if lang == "zh-cn" || lang == "zh-tw" {
+		return nil
+	}
+	if lang == "" || lang != strings.ToLower(lang) {
+		return fmt.Errorf("language %q is not a lowercase ISO 639 code", lang)
+	}
+	if _, err := language.ParseBase(lang); err != nil {
+		return fmt.Errorf("language %q is not an ISO 639 code", lang)
+	}
+	return nil
+}
+
+// hostList formats the hosts found by validateLoc, most frequent first.
+func hostList(hosts map[string]int) string {
+	list := make([]string, 0, len(hosts))
+	for host := range hosts {
+		list = append(list, host)
+	}
+	slices.SortFunc(list, func(a, b string) int {
+		if hosts[a] != hosts[b] {
+			return hosts[b] - hosts[a]
+		}
+		return strings.Compare(a, b)
+	})
+	return strings.Join(list, ", ")
+}
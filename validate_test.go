@@ -0,0 +1,181 @@
+package sitemap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// findRule returns the findings produced by rule.
+func findRule(findings []Finding, rule Rule) []Finding {
+	var out []Finding
+	for _, f := range findings {
+		if f.Rule == rule {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func TestValidateClean(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Now(), 1.0, Daily,
+		WithImages([]Image{{URL: "https://example.com/a.jpg"}}),
+		WithVideos([]Video{{
+			ThumbnailURL: "https://example.com/t.jpg",
+			Title:        "Title",
+			Description:  "Description",
+			PlayerURL:    "https://example.com/player",
+		}}),
+		WithGoogleNews(GoogleNews{SiteName: "Example", Language: "en", PublicationDate: time.Now().Add(-time.Hour), Title: "News"}),
+	)
+
+	if findings := sm.Validate(); findings != nil {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+}
+
+func TestValidateItemRules(t *testing.T) {
+	images := make([]Image, maxImagesPerURL+1)
+	for i := range images {
+		images[i] = Image{URL: "https://example.com/img.jpg"}
+	}
+
+	sm := New()
+	// Items are appended directly to bypass the checks in Add, as Parse does.
+	sm.items = []Item{
+		{URL: "https://example.com/" + strings.Repeat("a", maxLocLength)},
+		{URL: "https://example.com/images", Images: images},
+		{URL: "https://example.com/video", Videos: []Video{{
+			Title:       strings.Repeat("t", maxVideoTitleLength+1),
+			Description: strings.Repeat("d", maxVideoDescriptionLength+1),
+		}}},
+		{URL: "https://example.com/news", News: &GoogleNews{Language: "English"}},
+		{URL: "https://example.com/future", News: &GoogleNews{Language: "zh-cn", PublicationDate: time.Now().Add(time.Hour)}},
+		{URL: "/relative", Priority: 2},
+		{URL: "https://example.com/images"},
+		{URL: "https://example.com/mobile", Mobile: "tablet"},
+	}
+
+	findings := sm.Validate()
+
+	tests := []struct {
+		rule     Rule
+		index    int
+		field    string
+		severity Severity
+	}{
+		{RuleLocLength, 0, "loc", SeverityError},
+		{RuleImageCount, 1, "images", SeverityError},
+		{RuleVideoTitle, 2, "videos[0].title", SeverityError},
+		{RuleVideoDescription, 2, "videos[0].description", SeverityError},
+		{RuleVideoThumbnail, 2, "videos[0].thumbnail_loc", SeverityError},
+		{RuleVideoLocation, 2, "videos[0].content_loc", SeverityError},
+		{RuleNewsLanguage, 3, "news.language", SeverityError},
+		{RuleNewsPublicationDate, 3, "news.publication_date", SeverityError},
+		{RuleNewsPublicationDate, 4, "news.publication_date", SeverityWarning},
+		{RuleInvalidURL, 5, "loc", SeverityError},
+		{RulePriorityRange, 5, "priority", SeverityError},
+		{RuleDuplicateLoc, 6, "loc", SeverityWarning},
+		{RuleMobileType, 7, "mobile", SeverityError},
+	}
+
+	for _, tt := range tests {
+		found := false
+		for _, f := range findRule(findings, tt.rule) {
+			if f.Index == tt.index && f.Field == tt.field && f.Severity == tt.severity {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected %s %s finding for item %d field %s, got %v", tt.severity, tt.rule, tt.index, tt.field, findings)
+		}
+	}
+
+	if len(findRule(findings, RuleNewsLanguage)) != 1 {
+		t.Error("zh-cn should be accepted as a news language")
+	}
+
+	if !HasErrors(findings) {
+		t.Error("HasErrors() should report the errors")
+	}
+}
+
+func TestValidateMixedHosts(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/a", time.Now(), 0.5, Daily)
+	sm.Add("https://Example.com/b", time.Now(), 0.5, Daily)
+	if f := findRule(sm.Validate(), RuleMixedHosts); len(f) != 0 {
+		t.Errorf("Host comparison should ignore case, got %v", f)
+	}
+
+	sm.Add("https://www.example.com/c", time.Now(), 0.5, Daily)
+	f := findRule(sm.Validate(), RuleMixedHosts)
+	if len(f) != 1 {
+		t.Fatalf("Expected one mixed-hosts finding, got %v", f)
+	}
+	if f[0].Index != -1 || !strings.Contains(f[0].Message, "example.com, www.example.com") {
+		t.Errorf("Unexpected finding %v", f[0])
+	}
+}
+
+func TestValidateHreflang(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/en", time.Now(), 0.5, Daily, WithTranslations([]Translation{
+		{Language: "de", URL: "https://example.com/de"},
+	}))
+	sm.Add("https://example.com/de", time.Now(), 0.5, Daily)
+	sm.items[1].Langs = []Translation{{Language: "deutsch", URL: "https://example.com/de"}}
+
+	findings := sm.Validate()
+
+	if f := findRule(findings, RuleHreflangReciprocity); len(f) != 1 || f[0].Index != 0 || f[0].Field != "translations[0].url" {
+		t.Errorf("Expected one reciprocity finding for item 0, got %v", f)
+	}
+	if f := findRule(findings, RuleHreflangLanguage); len(f) != 1 || f[0].Index != 1 {
+		t.Errorf("Expected one language finding for item 1, got %v", f)
+	}
+}
+
+func TestValidateURLCount(t *testing.T) {
+	sm := New()
+	sm.items = make([]Item, defaultMaxURLs+1)
+	for i := range sm.items {
+		sm.items[i].URL = "https://example.com/"
+	}
+
+	if f := findRule(sm.Validate(), RuleURLCount); len(f) != 1 || f[0].Index != -1 {
+		t.Errorf("Expected one url-count finding, got %v", f)
+	}
+}
+
+func TestIndexValidate(t *testing.T) {
+	idx := NewIndex()
+	idx.Add("https://example.com/sitemap-1.xml", time.Now())
+	if findings := idx.Validate(); findings != nil {
+		t.Errorf("Expected no findings, got %v", findings)
+	}
+
+	idx.Add("https://example.com/sitemap-1.xml", time.Now())
+	idx.Add("https://cdn.example.com/sitemap-2.xml", time.Now())
+	idx.sitemaps = append(idx.sitemaps, IndexItem{URL: "ftp://example.com/sitemap-3.xml"})
+
+	findings := idx.Validate()
+	for _, rule := range []Rule{RuleDuplicateLoc, RuleMixedHosts, RuleInvalidURL} {
+		if len(findRule(findings, rule)) != 1 {
+			t.Errorf("Expected one %s finding, got %v", rule, findings)
+		}
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	f := Finding{Severity: SeverityError, Index: 3, Field: "loc", Rule: RuleLocLength, Message: "too long"}
+	if got := f.String(); got != "error: item 3: loc: loc-length: too long" {
+		t.Errorf("Unexpected String() %q", got)
+	}
+
+	f = Finding{Severity: SeverityWarning, Index: -1, Rule: RuleMixedHosts, Message: "two hosts"}
+	if got := f.String(); got != "warning: mixed-hosts: two hosts" {
+		t.Errorf("Unexpected String() %q", got)
+	}
+}
@@ -0,0 +1,70 @@
+package sitemap
+
+import "slices"
+
+// YesNo is an optional yes or no value of a video. The empty value leaves
+// the element out, so the search engine's default applies.
+type YesNo string
+
+const (
+	Yes YesNo = "yes"
+	No  YesNo = "no"
+)
+
+// Relationship says whether a video restriction or platform list names the
+// only allowed values or the denied ones.
+type Relationship string
+
+const (
+	Allow Relationship = "allow"
+	Deny  Relationship = "deny"
+)
+
+// VideoRestriction limits the countries where a video is shown.
+type VideoRestriction struct {
+	Relationship Relationship `json:"relationship"`
+	Countries    []string     `json:"countries"` // ISO 3166 alpha-2 codes, e.g. "IE"
+}
+
+// VideoPlatform limits the platforms a video is shown on.
+type VideoPlatform struct {
+	Relationship Relationship `json:"relationship"`
+	Platforms    []string     `json:"platforms"` // "web", "mobile" or "tv"
+}
+
+// VideoPrice is the price to download or view a video. A video can have
+// several prices, for example to rent and to own.
+type VideoPrice struct {
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`             // ISO 4217 code, e.g. "EUR"
+	Type       string  `json:"type,omitempty"`       // "rent" or "own"
+	Resolution string  `json:"resolution,omitempty"` // "HD" or "SD"
+}
+
+// VideoUploader is the name of the video's uploader with an optional page
+// about them.
+type VideoUploader struct {
+	Name string `json:"name"`
+	Info string `json:"info,omitempty"` // URL
+}
+
+// cloneVideo returns a copy of the video that shares no memory with it.
+func cloneVideo(v Video) Video {
+	if v.Restriction != nil {
+		r := *v.Restriction
+		r.Countries = slices.Clone(r.Countries)
+		v.Restriction = &r
+	}
+	if v.Platform != nil {
+		p := *v.Platform
+		p.Platforms = slices.Clone(p.Platforms)
+		v.Platform = &p
+	}
+	if v.Uploader != nil {
+		u := *v.Uploader
+		v.Uploader = &u
+	}
+	v.Prices = slices.Clone(v.Prices)
+	v.Tags = slices.Clone(v.Tags)
+	return v
+}
@@ -0,0 +1,254 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fullVideo() Video {
+	return Video{
+		ThumbnailURL:         "https://example.com/thumb.jpg",
+		Title:                "Grilling steaks",
+		Description:          "Alkis shows you how to get perfectly done steaks every time",
+		ContentURL:           "https://example.com/video.mp4",
+		PlayerURL:            "https://example.com/player?video=123",
+		Duration:             600,
+		ExpirationDate:       time.Date(2031, 11, 5, 19, 20, 30, 0, time.UTC),
+		Rating:               4.2,
+		ViewCount:            12345,
+		PublicationDate:      time.Date(2024, 11, 5, 19, 20, 30, 0, time.UTC),
+		FamilyFriendly:       Yes,
+		Restriction:          &VideoRestriction{Relationship: Allow, Countries: []string{"IE", "GB", "US", "CA"}},
+		Platform:             &VideoPlatform{Relationship: Allow, Platforms: []string{"web", "tv"}},
+		Prices:               []VideoPrice{{Amount: 1.99, Currency: "EUR", Type: "rent", Resolution: "HD"}, {Amount: 9.99, Currency: "EUR", Type: "own"}},
+		RequiresSubscription: Yes,
+		Uploader:             &VideoUploader{Name: "GrillyMcGrillerson", Info: "https://example.com/users?id=1&tab=videos"},
+		Live:                 No,
+		Tags:                 []string{"steak", "meat", "summer"},
+	}
+}
+
+func TestVideoXML(t *testing.T) {
+	sm := New()
+	if err := sm.Add("https://example.com/steaks", time.Time{}, 0, "", WithVideo(fullVideo())); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"<video:duration>600</video:duration>",
+		"<video:expiration_date>2031-11-05T19:20:30Z</video:expiration_date>",
+		"<video:rating>4.2</video:rating>",
+		"<video:view_count>12345</video:view_count>",
+		"<video:publication_date>2024-11-05T19:20:30Z</video:publication_date>",
+		"<video:family_friendly>yes</video:family_friendly>",
+		`<video:restriction relationship="allow">IE GB US CA</video:restriction>`,
+		`<video:platform relationship="allow">web tv</video:platform>`,
+		`<video:price currency="EUR" type="rent" resolution="HD">1.99</video:price>`,
+		`<video:price currency="EUR" type="own">9.99</video:price>`,
+		"<video:requires_subscription>yes</video:requires_subscription>",
+		`<video:uploader info="https://example.com/users?id=1&amp;tab=videos">GrillyMcGrillerson</video:uploader>`,
+		"<video:live>no</video:live>",
+		"<video:tag>steak</video:tag>",
+		"<video:tag>summer</video:tag>",
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(data), e) {
+			t.Errorf("XML() should contain %s", e)
+		}
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	w.AddItem(sm.Items()[0])
+	w.Close()
+	if !strings.Contains(buf.String(), expected[11]) {
+		t.Error("Writer should write the same video elements")
+	}
+}
+
+func TestVideoElementOrder(t *testing.T) {
+	sm := New()
+	if err := sm.Add("https://example.com/steaks", time.Time{}, 0, "", WithVideo(fullVideo())); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := sm.XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The element sequence of the sitemap-video/1.1 schema, leaving out
+	// elements Video doesn't support.
+	expected := []string{
+		"thumbnail_loc", "title", "description", "content_loc", "player_loc",
+		"duration", "expiration_date", "rating", "view_count", "publication_date",
+		"tag", "family_friendly", "restriction", "price", "requires_subscription",
+		"uploader", "platform", "live",
+	}
+
+	var got []string
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			depth++
+			// urlset > url > video > element
+			if depth == 4 && tok.Name.Space == videoNamespace && (len(got) == 0 || got[len(got)-1] != tok.Name.Local) {
+				got = append(got, tok.Name.Local)
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("Video elements in order %v, expected %v", got, expected)
+	}
+}
+
+func TestVideoOptionalFieldsOmitted(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Time{}, 0, "", WithVideo(Video{
+		ThumbnailURL: "https://example.com/t.jpg",
+		Title:        "Title",
+		Description:  "Description",
+		PlayerURL:    "https://example.com/player",
+	}))
+
+	data, _ := sm.XML()
+	for _, element := range []string{"rating", "view_count", "family_friendly", "restriction", "platform", "price", "uploader", "live", "tag"} {
+		if strings.Contains(string(data), "<video:"+element) {
+			t.Errorf("Unset %s should be left out", element)
+		}
+	}
+}
+
+func TestVideoParseRoundTrip(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/steaks", time.Time{}, 0, "", WithVideo(fullVideo()))
+	data, _ := sm.XML()
+
+	parsed, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.Items()[0].Videos[0]; !reflect.DeepEqual(got, fullVideo()) {
+		t.Errorf("Parsed video differs\ngot:      %+v\nexpected: %+v", got, fullVideo())
+	}
+}
+
+func TestVideoURLsResolved(t *testing.T) {
+	sm := NewWithOptions(&Options{BaseURL: "https://example.com"})
+	video := fullVideo()
+	video.Uploader = &VideoUploader{Name: "Grilly", Info: "/users/1"}
+	sm.Add("/steaks", time.Time{}, 0, "", WithVideo(video))
+
+	if got := sm.Items()[0].Videos[0].Uploader.Info; got != "https://example.com/users/1" {
+		t.Errorf("Uploader info should be resolved, got %q", got)
+	}
+	if video.Uploader.Info != "/users/1" {
+		t.Error("Add() should not modify the caller's uploader")
+	}
+}
+
+func TestVideoCopies(t *testing.T) {
+	sm := New()
+	sm.Add("https://example.com/", time.Time{}, 0, "", WithVideo(fullVideo()))
+
+	item := sm.Items()[0]
+	item.Videos[0].Tags[0] = "changed"
+	item.Videos[0].Restriction.Countries[0] = "FR"
+	item.Videos[0].Uploader.Name = "changed"
+
+	video := sm.Items()[0].Videos[0]
+	if video.Tags[0] != "steak" || video.Restriction.Countries[0] != "IE" || video.Uploader.Name != "GrillyMcGrillerson" {
+		t.Errorf("Modifying a copy changed the sitemap: %+v", video)
+	}
+}
+
+func TestVideoValidation(t *testing.T) {
+	sm := New()
+	if f := sm.Validate(); f != nil {
+		t.Fatalf("Expected no findings, got %v", f)
+	}
+	sm.Add("https://example.com/", time.Time{}, 0, "", WithVideo(fullVideo()))
+	if f := sm.Validate(); f != nil {
+		t.Errorf("Expected no findings for a complete video, got %v", f)
+	}
+
+	bad := fullVideo()
+	bad.Duration = 30000
+	bad.Rating = 5.5
+	bad.ExpirationDate = time.Now().Add(-time.Hour)
+	bad.Live = "true"
+	bad.Restriction = &VideoRestriction{Relationship: "only", Countries: []string{"ie", "XX"}}
+	bad.Platform = &VideoPlatform{Relationship: Deny, Platforms: []string{"desktop"}}
+	bad.Prices = []VideoPrice{{Amount: -1, Currency: "EURO", Type: "buy", Resolution: "4K"}}
+	bad.Uploader = &VideoUploader{Name: strings.Repeat("u", maxVideoUploaderLength+1), Info: "/relative"}
+	bad.Tags = make([]string, maxVideoTags+1)
+	sm.items[0].Videos = []Video{bad}
+
+	findings := sm.Validate()
+
+	tests := []struct {
+		rule     Rule
+		field    string
+		count    int
+		severity Severity
+	}{
+		{RuleVideoDuration, "videos[0].duration", 1, SeverityError},
+		{RuleVideoRating, "videos[0].rating", 1, SeverityError},
+		{RuleVideoExpiration, "videos[0].expiration_date", 1, SeverityWarning},
+		{RuleVideoFlag, "videos[0].live", 1, SeverityError},
+		{RuleVideoRestriction, "videos[0].restriction", 3, SeverityError},
+		{RuleVideoPlatform, "videos[0].platform", 1, SeverityError},
+		{RuleVideoPrice, "videos[0].prices[0]", 4, SeverityError},
+		{RuleVideoUploader, "videos[0].uploader", 1, SeverityError},
+		{RuleInvalidURL, "videos[0].uploader.info", 1, SeverityError},
+		{RuleVideoTags, "videos[0].tags", 1, SeverityError},
+	}
+
+	for _, tt := range tests {
+		got := findRule(findings, tt.rule)
+		if len(got) != tt.count {
+			t.Errorf("Expected %d %s findings, got %v", tt.count, tt.rule, got)
+			continue
+		}
+		if got[0].Field != tt.field || got[0].Severity != tt.severity {
+			t.Errorf("Unexpected %s finding %v", tt.rule, got[0])
+		}
+	}
+	// Zero leaves the duration out; negative values are reported.
+	for _, duration := range []int{0, -1} {
+		video := fullVideo()
+		video.Duration = duration
+		sm.items[0].Videos = []Video{video}
+
+		got := findRule(sm.Validate(), RuleVideoDuration)
+		if duration == 0 && len(got) != 0 {
+			t.Errorf("Duration 0 should be accepted, got %v", got)
+		}
+		if duration < 0 && (len(got) != 1 || !strings.Contains(got[0].Message, "or 0 to leave it out")) {
+			t.Errorf("Duration %d should be reported, got %v", duration, got)
+		}
+	}
+}
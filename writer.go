@@ -0,0 +1,289 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"time"
+)
+
+// Namespace is a set of sitemap extension namespaces.
+type Namespace uint8
+
+const (
+	NamespaceImage Namespace = 1 << iota
+	NamespaceVideo
+	NamespaceNews
+	NamespaceXHTML
+
+	// AllNamespaces declares every extension namespace the package supports.
+	AllNamespaces = NamespaceImage | NamespaceVideo | NamespaceNews | NamespaceXHTML
+)
+
+// String returns the prefixes of the namespaces in the set, such as
+// "image video".
+func (n Namespace) String() string {
+	var names []string
+	for _, ns := range []struct {
+		ns   Namespace
+		name string
+	}{
+		{NamespaceImage, "image"},
+		{NamespaceVideo, "video"},
+		{NamespaceNews, "news"},
+		{NamespaceXHTML, "xhtml"},
+	} {
+		if n&ns.ns != 0 {
+			names = append(names, ns.name)
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// namespaces returns the extension namespaces the item's elements use.
+func (i Item) namespaces() Namespace {
+	var n Namespace
+	if len(i.Images) > 0 {
+		n |= NamespaceImage
+	}
+	if len(i.Videos) > 0 {
+		n |= NamespaceVideo
+	}
+	if i.News != nil {
+		n |= NamespaceNews
+	}
+	if len(i.Alternates) > 0 || len(i.Langs) > 0 {
+		n |= NamespaceXHTML
+	}
+	return n
+}
+
+// checkNamespaces returns a *NamespaceError if the item uses namespaces
+// that declared leaves out.
+func checkNamespaces(item Item, declared Namespace) error {
+	if missing := item.namespaces() &^ declared; missing != 0 {
+		return &NamespaceError{URL: item.URL, Missing: missing}
+	}
+	return nil
+}
+
+// urlsetEnd is the indented closing tag written by Close.
+const urlsetEnd = "\n</urlset>"
+
+// Writer streams a sitemap to an io.Writer, encoding each item as it is
+// added instead of keeping all items in memory.
+//
+// Because the items are not known ahead of time, the extension namespaces
+// are declared up front from Options.Namespaces. Options.BaseURL and
+// Options.Normalize are applied, but Options.Duplicates is not, as that
+// would require remembering every location written. Call Close to finish
+// the document; Close does not close the underlying io.Writer.
+type Writer struct {
+	w       io.Writer
+	opts    Options
+	buf     bytes.Buffer
+	enc     *xml.Encoder
+	count   int
+	written int64
+	started bool
+	closed  bool
+	err     error
+}
+
+// NewWriter creates a streaming sitemap writer. A nil opts uses the defaults.
+func NewWriter(w io.Writer, opts *Options) *Writer {
+	sw := &Writer{w: w}
+	if opts != nil {
+		sw.opts = *opts
+	}
+	if sw.opts.MaxURLs <= 0 {
+		sw.opts.MaxURLs = defaultMaxURLs
+	}
+	if sw.opts.MaxBytes <= 0 {
+		sw.opts.MaxBytes = defaultMaxBytes
+	}
+	if sw.opts.Namespaces == 0 {
+		sw.opts.Namespaces = AllNamespaces
+	}
+
+	sw.enc = xml.NewEncoder(&sw.buf)
+	sw.enc.Indent("", "  ")
+	return sw
+}
+
+// Add encodes a URL with the specified parameters.
+func (w *Writer) Add(loc string, lastMod time.Time, priority float64, changeFreq ChangeFreq, opts ...Option) error {
+	item := Item{
+		URL:        loc,
+		LastMod:    lastMod,
+		Priority:   priority,
+		ChangeFreq: changeFreq,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(&item)
+	}
+
+	return w.AddItem(item)
+}
+
+// AddItem encodes a pre-configured item. It fails without writing anything
+// if the item would exceed Options.MaxURLs or Options.MaxBytes, or uses an
+// extension namespace that Options.Namespaces leaves out.
+func (w *Writer) AddItem(item Item) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.closed {
+		return ErrClosed
+	}
+
+	if w.count >= w.opts.MaxURLs {
+		return fmt.Errorf("%w of %d", ErrLimitReached, w.opts.MaxURLs)
+	}
+
+	item, err := prepareItem(item, &w.opts)
+	if err != nil {
+		return err
+	}
+	if err := checkNamespaces(item, w.opts.Namespaces); err != nil {
+		return err
+	}
+
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if err := w.enc.EncodeElement(toXMLItem(item, w.opts.Dates), xml.StartElement{Name: xml.Name{Local: "url"}}); err != nil {
+		return w.fail(err)
+	}
+	if err := w.enc.Flush(); err != nil {
+		return w.fail(err)
+	}
+	data := escapeEntities(w.buf.Bytes())
+	w.buf.Reset()
+
+	// Leave room for the closing tag so Close never exceeds the limit.
+	if w.written+int64(len(data)+len(urlsetEnd)) > w.opts.MaxBytes {
+		return fmt.Errorf("%w of %d bytes", ErrSizeLimitReached, w.opts.MaxBytes)
+	}
+
+	if err := w.write(data); err != nil {
+		return err
+	}
+
+	w.count++
+	return nil
+}
+
+// AddSeq writes the items produced by seq, such as rows read from a
+// database cursor, as they arrive. It stops at the first error, either from
+// seq or from AddItem, and returns it with the item's position.
+func (w *Writer) AddSeq(seq iter.Seq2[Item, error]) error {
+	i := 0
+	for item, err := range seq {
+		if err == nil {
+			err = w.AddItem(item)
+		}
+		if err != nil {
+			return fmt.Errorf("item %d: %w", i, err)
+		}
+		i++
+	}
+	return nil
+}
+
+// Count returns the number of URLs written so far.
+func (w *Writer) Count() int {
+	return w.count
+}
+
+// Written returns the number of bytes written to the underlying io.Writer.
+func (w *Writer) Written() int64 {
+	return w.written
+}
+
+// Close writes the closing urlset tag. Calling Close more than once is a no-op.
+func (w *Writer) Close() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.closed {
+		return nil
+	}
+
+	if err := w.start(); err != nil {
+		return err
+	}
+
+	if err := w.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: "urlset"}}); err != nil {
+		return w.fail(err)
+	}
+
+	if err := w.flush(); err != nil {
+		return err
+	}
+
+	w.closed = true
+	return nil
+}
+
+// start writes the XML declaration and the opening urlset tag once.
+func (w *Writer) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+
+	writeHeader(&w.buf, w.opts.Stylesheet)
+
+	attrs := []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: sitemapNamespace}}
+	if w.opts.Namespaces&NamespaceImage != 0 {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:image"}, Value: imageNamespace})
+	}
+	if w.opts.Namespaces&NamespaceVideo != 0 {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:video"}, Value: videoNamespace})
+	}
+	if w.opts.Namespaces&NamespaceNews != 0 {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:news"}, Value: newsNamespace})
+	}
+	if w.opts.Namespaces&NamespaceXHTML != 0 {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:xhtml"}, Value: xhtmlNamespace})
+	}
+
+	if err := w.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: "urlset"}, Attr: attrs}); err != nil {
+		return w.fail(err)
+	}
+
+	return w.flush()
+}
+
+// flush moves everything encoded so far to the underlying io.Writer.
+func (w *Writer) flush() error {
+	if err := w.enc.Flush(); err != nil {
+		return w.fail(err)
+	}
+	data := escapeEntities(w.buf.Bytes())
+	w.buf.Reset()
+	return w.write(data)
+}
+
+// write writes encoded data to the underlying io.Writer.
+func (w *Writer) write(data []byte) error {
+	n, err := w.w.Write(data)
+	w.written += int64(n)
+	if err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+// fail records the first write error; the writer is unusable afterwards.
+func (w *Writer) fail(err error) error {
+	w.err = err
+	return err
+}
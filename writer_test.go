@@ -0,0 +1,277 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	now := time.Now().Truncate(time.Second)
+
+	err := w.Add("https://example.com/", now, 1.0, Daily)
+	if err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	err = w.AddItem(Item{
+		URL:    "https://example.com/gallery",
+		Images: []Image{{URL: "https://example.com/img.jpg", Title: "Image"}},
+	})
+	if err != nil {
+		t.Fatalf("AddItem() failed: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if w.Count() != 2 {
+		t.Errorf("Expected 2 items, got %d", w.Count())
+	}
+
+	if w.Written() != int64(buf.Len()) {
+		t.Errorf("Written() = %d, expected %d", w.Written(), buf.Len())
+	}
+
+	xmlStr := buf.String()
+	if !strings.HasPrefix(xmlStr, `<?xml version="1.0" encoding="UTF-8"?>`) {
+		t.Error("Output should start with XML declaration")
+	}
+
+	for _, ns := range []string{sitemapNamespace, imageNamespace, videoNamespace, newsNamespace, xhtmlNamespace} {
+		if !strings.Contains(xmlStr, ns) {
+			t.Errorf("Output should declare namespace %s", ns)
+		}
+	}
+
+	if !strings.HasSuffix(xmlStr, "</urlset>") {
+		t.Error("Output should end with closing urlset tag")
+	}
+
+	var urlset struct {
+		URLs []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &urlset); err != nil {
+		t.Fatalf("Failed to unmarshal writer output: %v", err)
+	}
+
+	if len(urlset.URLs) != 2 {
+		t.Fatalf("Expected 2 URLs, got %d", len(urlset.URLs))
+	}
+
+	if urlset.URLs[1].Loc != "https://example.com/gallery" {
+		t.Errorf("Unexpected second loc %s", urlset.URLs[1].Loc)
+	}
+}
+
+func TestWriterMatchesXML(t *testing.T) {
+	sm := New()
+	now := time.Now().Truncate(time.Second)
+
+	sm.Add("https://example.com/", now, 1.0, Daily)
+	sm.Add("https://example.com/news", now, 0.8, Hourly, WithGoogleNews(GoogleNews{
+		SiteName:        "Example",
+		Language:        "en",
+		PublicationDate: now,
+		Title:           "Headline",
+	}))
+
+	expected, err := sm.XML()
+	if err != nil {
+		t.Fatalf("XML() failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Namespaces: NamespaceNews})
+	for _, item := range sm.Items() {
+		if err := w.AddItem(item); err != nil {
+			t.Fatalf("AddItem() failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	if buf.String() != string(expected) {
+		t.Errorf("Writer output differs from XML()\ngot:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriterNamespaces(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Namespaces: NamespaceImage | NamespaceXHTML})
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+
+	xmlStr := buf.String()
+	if !strings.Contains(xmlStr, `xmlns:image="`+imageNamespace+`"`) {
+		t.Error("Output should declare the image namespace")
+	}
+	if !strings.Contains(xmlStr, `xmlns:xhtml="`+xhtmlNamespace+`"`) {
+		t.Error("Output should declare the xhtml namespace")
+	}
+	if strings.Contains(xmlStr, "xmlns:video") || strings.Contains(xmlStr, "xmlns:news") {
+		t.Error("Output should not declare unselected namespaces")
+	}
+}
+
+func TestWriterUndeclaredNamespace(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{Namespaces: NamespaceImage})
+
+	err := w.Add("https://example.com/", time.Time{}, 0.5, Daily,
+		WithImage(Image{URL: "https://example.com/a.jpg"}),
+		WithVideo(Video{Title: "Clip", ThumbnailURL: "https://example.com/t.jpg", ContentURL: "https://example.com/v.mp4"}),
+		WithTranslation(Translation{Language: "de", URL: "https://example.com/de"}))
+	var nsErr *NamespaceError
+	if !errors.As(err, &nsErr) || !errors.Is(err, ErrNamespace) {
+		t.Fatalf("Expected a *NamespaceError, got %v", err)
+	}
+	if nsErr.Missing != NamespaceVideo|NamespaceXHTML || nsErr.URL != "https://example.com/" {
+		t.Errorf("Unexpected error %v", nsErr)
+	}
+	if !strings.HasSuffix(err.Error(), ": video xhtml") {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	if err := w.Add("https://example.com/img", time.Time{}, 0.5, Daily, WithImage(Image{URL: "https://example.com/a.jpg"})); err != nil {
+		t.Fatalf("Add() failed for a declared namespace: %v", err)
+	}
+	w.Close()
+	if w.Count() != 1 || strings.Contains(buf.String(), "<video:") {
+		t.Errorf("The rejected item should not be written:\n%s", buf.String())
+	}
+
+	// A Splitter rejects the item before opening a part for it.
+	files := &memoryFiles{}
+	s := NewSplitter(files.create, &SplitOptions{Options: Options{Namespaces: NamespaceImage}, URL: "https://example.com/"})
+	err = s.Add("https://example.com/", time.Time{}, 0.5, Daily, WithGoogleNews(GoogleNews{}))
+	if !errors.Is(err, ErrNamespace) {
+		t.Errorf("Expected ErrNamespace from the Splitter, got %v", err)
+	}
+	if len(files.names) != 0 {
+		t.Errorf("No part should be created, got %v", files.names)
+	}
+}
+
+func TestWriterMaxURLs(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, &Options{MaxURLs: 1})
+	now := time.Now()
+
+	if err := w.Add("https://example.com/1", now, 0.5, Daily); err != nil {
+		t.Fatalf("Add() failed: %v", err)
+	}
+
+	if err := w.Add("https://example.com/2", now, 0.5, Daily); err == nil {
+		t.Error("Add() should fail once MaxURLs is reached")
+	}
+}
+
+func TestWriterValidation(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+
+	if err := w.AddItem(Item{URL: "/relative"}); err == nil {
+		t.Error("AddItem() should fail for relative URL")
+	}
+
+	if err := w.AddItem(Item{URL: "https://example.com/", Priority: 1.5}); err == nil {
+		t.Error("AddItem() should fail for invalid priority")
+	}
+
+	if w.Count() != 0 {
+		t.Errorf("Expected 0 items, got %d", w.Count())
+	}
+}
+
+func TestWriterClose(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Second Close() should be a no-op, got %v", err)
+	}
+
+	if strings.Count(buf.String(), "</urlset>") != 1 {
+		t.Error("Closing urlset tag should be written exactly once")
+	}
+
+	if err := w.AddItem(Item{URL: "https://example.com/"}); err == nil {
+		t.Error("AddItem() should fail after Close()")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestWriterWriteError(t *testing.T) {
+	w := NewWriter(failingWriter{}, nil)
+
+	err := w.AddItem(Item{URL: "https://example.com/"})
+	if err == nil {
+		t.Fatal("AddItem() should fail when the underlying writer fails")
+	}
+
+	if err := w.Close(); err == nil {
+		t.Error("Close() should return the sticky write error")
+	}
+}
+
+// rows simulates a database cursor that fails after n rows.
+func rows(n int, failure error) iter.Seq2[Item, error] {
+	return func(yield func(Item, error) bool) {
+		for i := range n {
+			if !yield(Item{URL: fmt.Sprintf("https://example.com/%d", i)}, nil) {
+				return
+			}
+		}
+		if failure != nil {
+			yield(Item{}, failure)
+		}
+	}
+}
+
+func TestWriterAddSeq(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	if err := w.AddSeq(rows(3, nil)); err != nil {
+		t.Fatalf("AddSeq() failed: %v", err)
+	}
+	if w.Count() != 3 {
+		t.Errorf("Expected 3 items, got %d", w.Count())
+	}
+
+	failure := errors.New("connection reset")
+	err := w.AddSeq(rows(2, failure))
+	if !errors.Is(err, failure) || !strings.HasPrefix(err.Error(), "item 2: ") {
+		t.Errorf("Expected the source error at item 2, got %v", err)
+	}
+	if w.Count() != 5 {
+		t.Errorf("Items before the failure should be written, got %d", w.Count())
+	}
+
+	bad := func(yield func(Item, error) bool) {
+		yield(Item{URL: "/relative"}, nil)
+	}
+	if err := w.AddSeq(bad); !errors.Is(err, ErrRelativeURL) {
+		t.Errorf("Expected ErrRelativeURL, got %v", err)
+	}
+}
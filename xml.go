@@ -4,8 +4,8 @@
 	"bytes"
 	"encoding/xml"
 	"fmt"
-	"html"
-	"time"
+	"strconv"
+	"strings"
 )
 
 // URLSet represents the root element of a sitemap XML.
@@ -15,6 +15,7 @@ type URLSet struct {
 	Image   string    `xml:"xmlns:image,attr,omitempty"`
 	Video   string    `xml:"xmlns:video,attr,omitempty"`
 	News    string    `xml:"xmlns:news,attr,omitempty"`
+	XHTML   string    `xml:"xmlns:xhtml,attr,omitempty"`
 	URLs    []XMLItem `xml:"url"`
 }
 
@@ -37,22 +38,58 @@ type XMLImage struct {
 	Caption string `xml:"image:caption,omitempty"`
 }
 
-// XMLVideo represents a video in XML format.
+// XMLVideo represents a video in XML format. The fields follow the element
+// sequence of the sitemap-video/1.1 schema.
 type XMLVideo struct {
-	ThumbnailURL string `xml:"video:thumbnail_loc"`
-	Title        string `xml:"video:title"`
-	Description  string `xml:"video:description"`
-	ContentURL   string `xml:"video:content_loc,omitempty"`
-	PlayerURL    string `xml:"video:player_loc,omitempty"`
-	Duration     string `xml:"video:duration,omitempty"`
+	ThumbnailURL         string                `xml:"video:thumbnail_loc"`
+	Title                string                `xml:"video:title"`
+	Description          string                `xml:"video:description"`
+	ContentURL           string                `xml:"video:content_loc,omitempty"`
+	PlayerURL            string                `xml:"video:player_loc,omitempty"`
+	Duration             string                `xml:"video:duration,omitempty"`
+	ExpirationDate       string                `xml:"video:expiration_date,omitempty"`
+	Rating               string                `xml:"video:rating,omitempty"`
+	ViewCount            string                `xml:"video:view_count,omitempty"`
+	PublicationDate      string                `xml:"video:publication_date,omitempty"`
+	Tags                 []string              `xml:"video:tag,omitempty"`
+	FamilyFriendly       string                `xml:"video:family_friendly,omitempty"`
+	Restriction          *XMLVideoRelationship `xml:"video:restriction,omitempty"`
+	Prices               []XMLVideoPrice       `xml:"video:price,omitempty"`
+	RequiresSubscription string                `xml:"video:requires_subscription,omitempty"`
+	Uploader             *XMLVideoUploader     `xml:"video:uploader,omitempty"`
+	Platform             *XMLVideoRelationship `xml:"video:platform,omitempty"`
+	Live                 string                `xml:"video:live,omitempty"`
+}
+
+// XMLVideoRelationship represents a video restriction or platform list.
+type XMLVideoRelationship struct {
+	Relationship string `xml:"relationship,attr"`
+	Values       string `xml:",chardata"`
+}
+
+// XMLVideoPrice represents a video price in XML format.
+type XMLVideoPrice struct {
+	Currency   string `xml:"currency,attr"`
+	Type       string `xml:"type,attr,omitempty"`
+	Resolution string `xml:"resolution,attr,omitempty"`
+	Amount     string `xml:",chardata"`
+}
+
+// XMLVideoUploader represents a video uploader in XML format.
+type XMLVideoUploader struct {
+	Info string `xml:"info,attr,omitempty"`
+	Name string `xml:",chardata"`
 }
 
 // XMLGoogleNews represents Google News metadata in XML format.
 type XMLGoogleNews struct {
 	Publication     XMLNewsPublication `xml:"news:publication"`
+	Access          string             `xml:"news:access,omitempty"`
+	Genres          string             `xml:"news:genres,omitempty"`
 	PublicationDate string             `xml:"news:publication_date"`
 	Title           string             `xml:"news:title"`
 	Keywords        string             `xml:"news:keywords,omitempty"`
+	StockTickers    string             `xml:"news:stock_tickers,omitempty"`
 }
 
 // XMLNewsPublication represents the news publication info.
@@ -69,131 +106,200 @@ type XMLAlternate struct {
 	Href     string `xml:"href,attr"`
 }
 
+// Namespace URIs used by the sitemap protocol and its extensions.
+const (
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	imageNamespace   = "http://www.google.com/schemas/sitemap-image/1.1"
+	videoNamespace   = "http://www.google.com/schemas/sitemap-video/1.1"
+	newsNamespace    = "http://www.google.com/schemas/sitemap-news/0.9"
+	xhtmlNamespace   = "http://www.w3.org/1999/xhtml"
+
+	googleMobileNamespace = "http://www.google.com/schemas/sitemap-mobile/1.0"
+	baiduMobileNamespace  = "http://www.baidu.com/schemas/sitemap-mobile/1/"
+)
+
 // XML generates the XML representation of the sitemap.
 func (s *Sitemap) XML() ([]byte, error) {
 	urlset := URLSet{
-		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		Xmlns: sitemapNamespace,
 		URLs:  make([]XMLItem, 0, len(s.items)),
 	}
 
-	// Check if we need namespace declarations
-	hasImages, hasVideos, hasNews := false, false, false
+	// Declare only the namespaces the items use.
+	var used Namespace
 	for _, item := range s.items {
-		if len(item.Images) > 0 {
-			hasImages = true
-		}
-		if len(item.Videos) > 0 {
-			hasVideos = true
-		}
-		if item.News != nil {
-			hasNews = true
-		}
+		used |= item.namespaces()
 	}
 
-	if hasImages {
-		urlset.Image = "http://www.google.com/schemas/sitemap-image/1.1"
+	if used&NamespaceImage != 0 {
+		urlset.Image = imageNamespace
+	}
+	if used&NamespaceVideo != 0 {
+		urlset.Video = videoNamespace
 	}
-	if hasVideos {
-		urlset.Video = "http://www.google.com/schemas/sitemap-video/1.1"
+	if used&NamespaceNews != 0 {
+		urlset.News = newsNamespace
 	}
-	if hasNews {
-		urlset.News = "http://www.google.com/schemas/sitemap-news/0.9"
+	if used&NamespaceXHTML != 0 {
+		urlset.XHTML = xhtmlNamespace
 	}
 
 	// Convert items to XML format
 	for _, item := range s.items {
-		xmlItem := XMLItem{
-			URL: html.EscapeString(item.URL),
-		}
+		urlset.URLs = append(urlset.URLs, toXMLItem(item, s.opts.Dates))
+	}
 
-		if !item.LastMod.IsZero() {
-			xmlItem.LastMod = item.LastMod.Format(time.RFC3339)
-		}
+	// Generate XML
+	var buf bytes.Buffer
+	writeHeader(&buf, s.opts.Stylesheet)
 
-		if item.ChangeFreq != "" {
-			xmlItem.ChangeFreq = string(item.ChangeFreq)
-		}
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(urlset); err != nil {
+		return nil, err
+	}
 
-		if item.Priority > 0 {
-			xmlItem.Priority = formatPriority(item.Priority)
-		}
+	return escapeEntities(buf.Bytes()), nil
+}
+
+// toXMLItem converts an item to its XML representation. Values are escaped
+// by the XML encoder; characters that XML 1.0 doesn't allow are dropped.
+func toXMLItem(item Item, dates DateFormat) XMLItem {
+	xmlItem := XMLItem{
+		URL: sanitizeText(item.URL),
+	}
+
+	if !item.LastMod.IsZero() {
+		xmlItem.LastMod = dates.format(item.LastMod)
+	}
+
+	if item.ChangeFreq != "" {
+		xmlItem.ChangeFreq = string(item.ChangeFreq)
+	}
 
-		// Add images
-		if len(item.Images) > 0 {
-			xmlItem.Images = make([]XMLImage, len(item.Images))
-			for i, img := range item.Images {
-				xmlItem.Images[i] = XMLImage{
-					URL:     html.EscapeString(img.URL),
-					Title:   html.EscapeString(img.Title),
-					Caption: html.EscapeString(img.Caption),
-				}
+	if item.hasPriority() {
+		xmlItem.Priority = formatPriority(item.Priority)
+	}
+
+	// Add images
+	if len(item.Images) > 0 {
+		xmlItem.Images = make([]XMLImage, len(item.Images))
+		for i, img := range item.Images {
+			xmlItem.Images[i] = XMLImage{
+				URL:     sanitizeText(img.URL),
+				Title:   sanitizeText(img.Title),
+				Caption: sanitizeText(img.Caption),
 			}
 		}
+	}
 
-		// Add videos
-		if len(item.Videos) > 0 {
-			xmlItem.Videos = make([]XMLVideo, len(item.Videos))
-			for i, video := range item.Videos {
-				xmlItem.Videos[i] = XMLVideo{
-					ThumbnailURL: html.EscapeString(video.ThumbnailURL),
-					Title:        html.EscapeString(video.Title),
-					Description:  html.EscapeString(video.Description),
-					ContentURL:   html.EscapeString(video.ContentURL),
-					PlayerURL:    html.EscapeString(video.PlayerURL),
-				}
-				if video.Duration > 0 {
-					xmlItem.Videos[i].Duration = formatDuration(video.Duration)
-				}
-			}
+	// Add videos
+	if len(item.Videos) > 0 {
+		xmlItem.Videos = make([]XMLVideo, len(item.Videos))
+		for i, video := range item.Videos {
+			xmlItem.Videos[i] = toXMLVideo(video, dates)
 		}
+	}
 
-		// Add Google News
-		if item.News != nil {
-			xmlItem.News = &XMLGoogleNews{
-				Publication: XMLNewsPublication{
-					Name:     html.EscapeString(item.News.SiteName),
-					Language: item.News.Language,
-				},
-				PublicationDate: item.News.PublicationDate.Format(time.RFC3339),
-				Title:           html.EscapeString(item.News.Title),
-				Keywords:        html.EscapeString(item.News.Keywords),
-			}
+	// Add Google News
+	if item.News != nil {
+		xmlItem.News = &XMLGoogleNews{
+			Publication: XMLNewsPublication{
+				Name:     sanitizeText(item.News.SiteName),
+				Language: sanitizeText(item.News.Language),
+			},
+			Access:          sanitizeText(item.News.Access),
+			Genres:          sanitizeText(item.News.Genres),
+			PublicationDate: dates.format(item.News.PublicationDate),
+			Title:           sanitizeText(item.News.Title),
+			Keywords:        sanitizeText(item.News.Keywords),
+			StockTickers:    sanitizeText(item.News.StockTickers),
 		}
+	}
 
-		// Add alternates as xhtml:link elements
-		if len(item.Alternates) > 0 || len(item.Langs) > 0 {
-			for _, alt := range item.Alternates {
-				xmlItem.Alternates = append(xmlItem.Alternates, XMLAlternate{
-					Rel:   "alternate",
-					Media: alt.Media,
-					Href:  html.EscapeString(alt.URL),
-				})
-			}
+	// Add alternates as xhtml:link elements
+	for _, alt := range item.Alternates {
+		xmlItem.Alternates = append(xmlItem.Alternates, XMLAlternate{
+			Rel:   "alternate",
+			Media: sanitizeText(alt.Media),
+			Href:  sanitizeText(alt.URL),
+		})
+	}
 
-			for _, lang := range item.Langs {
-				xmlItem.Alternates = append(xmlItem.Alternates, XMLAlternate{
-					Rel:      "alternate",
-					Hreflang: lang.Language,
-					Href:     html.EscapeString(lang.URL),
-				})
-			}
+	for _, lang := range item.Langs {
+		xmlItem.Alternates = append(xmlItem.Alternates, XMLAlternate{
+			Rel:      "alternate",
+			Hreflang: sanitizeText(lang.Language),
+			Href:     sanitizeText(lang.URL),
+		})
+	}
+
+	return xmlItem
+}
+
+// toXMLVideo converts a video to its XML representation.
+func toXMLVideo(video Video, dates DateFormat) XMLVideo {
+	v := XMLVideo{
+		ThumbnailURL:         sanitizeText(video.ThumbnailURL),
+		Title:                sanitizeText(video.Title),
+		Description:          sanitizeText(video.Description),
+		ContentURL:           sanitizeText(video.ContentURL),
+		PlayerURL:            sanitizeText(video.PlayerURL),
+		FamilyFriendly:       sanitizeText(string(video.FamilyFriendly)),
+		RequiresSubscription: sanitizeText(string(video.RequiresSubscription)),
+		Live:                 sanitizeText(string(video.Live)),
+	}
+
+	if video.Duration > 0 {
+		v.Duration = formatDuration(video.Duration)
+	}
+	if !video.ExpirationDate.IsZero() {
+		v.ExpirationDate = dates.format(video.ExpirationDate)
+	}
+	if video.Rating > 0 {
+		v.Rating = strconv.FormatFloat(video.Rating, 'f', -1, 64)
+	}
+	if video.ViewCount > 0 {
+		v.ViewCount = strconv.Itoa(video.ViewCount)
+	}
+	if !video.PublicationDate.IsZero() {
+		v.PublicationDate = dates.format(video.PublicationDate)
+	}
+
+	if r := video.Restriction; r != nil {
+		v.Restriction = &XMLVideoRelationship{
+			Relationship: sanitizeText(string(r.Relationship)),
+			Values:       sanitizeText(strings.Join(r.Countries, " ")),
+		}
+	}
+	if p := video.Platform; p != nil {
+		v.Platform = &XMLVideoRelationship{
+			Relationship: sanitizeText(string(p.Relationship)),
+			Values:       sanitizeText(strings.Join(p.Platforms, " ")),
 		}
+	}
 
-		urlset.URLs = append(urlset.URLs, xmlItem)
+	for _, price := range video.Prices {
+		v.Prices = append(v.Prices, XMLVideoPrice{
+			Currency:   sanitizeText(price.Currency),
+			Type:       sanitizeText(price.Type),
+			Resolution: sanitizeText(price.Resolution),
+			Amount:     strconv.FormatFloat(price.Amount, 'f', -1, 64),
+		})
 	}
 
-	// Generate XML
-	var buf bytes.Buffer
-	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>`)
-	buf.WriteByte('\n')
+	if u := video.Uploader; u != nil {
+		v.Uploader = &XMLVideoUploader{
+			Info: sanitizeText(u.Info),
+			Name: sanitizeText(u.Name),
+		}
+	}
 
-	encoder := xml.NewEncoder(&buf)
-	encoder.Indent("", "  ")
-	if err := encoder.Encode(urlset); err != nil {
-		return nil, err
+	for _, tag := range video.Tags {
+		v.Tags = append(v.Tags, sanitizeText(tag))
 	}
 
-	return buf.Bytes(), nil
+	return v
 }
 
 // formatPriority formats priority value for XML output.